@@ -25,14 +25,89 @@ package main
 // GOPATH = %USERPROFILE%\go
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"os"
 	"serpent/io"
 	"serpent/piton"
+	"serpent/piton/evolve"
 	"strings"
 )
 
 func main() {
+	levelFile := flag.String("level", "", "plain-text level file to play instead of the bare board")
+	difficultyName := flag.String("difficulty", "", "pace of the game: "+strings.Join(piton.DifficultyNames(), ", ")+" (default normal)")
+	seed := flag.Int64("seed", 0, "seed of the game, to play the same game again (default from the clock)")
+	wrapEdges := flag.String("wrap", "", "edges the snake goes through: all, horizontal, vertical or a list like left,top")
+	rewind := flag.Int("rewind", 0, "practice game where Backspace takes back this many ticks")
+	saveFile := flag.String("save", "serpent.sav", "file the game is saved to from the in-game menu and loaded from")
+	themeName := flag.String("theme", "", "look of the board: "+strings.Join(piton.ThemeNames(), ", ")+" or a theme file (default classic)")
+	minimap := flag.Bool("minimap", false, "show a small map of the whole board when it doesn't fit in the terminal")
+	agentName := flag.String("agent", "random", "computer player that plays when you choose a: "+strings.Join(piton.AgentNames(), ", "))
+	replayFile := flag.String("replay", "", "replay file, like the ones written by the evolve package, the computer plays when you choose a")
+	scoresFile := flag.String("scores", "serpent.scores", "file that keeps the best score of every board")
+	flag.Parse()
+
+	if *difficultyName != "" {
+		difficulty, err := piton.DifficultyByName(*difficultyName)
+		if err != nil {
+			fmt.Println("-difficulty:", err)
+			os.Exit(1)
+		}
+		piton.SetDifficulty(difficulty)
+	}
+
+	agent, err := piton.NewAgent(*agentName)
+	if err != nil {
+		fmt.Println("-agent:", err)
+		os.Exit(1)
+	}
+	piton.SetAgent(agent)
+	piton.SetRenderer(piton.NullRenderer{})
+
+	if *themeName != "" {
+		theme, err := loadTheme(*themeName)
+		if err != nil {
+			fmt.Println("-theme:", err)
+			os.Exit(1)
+		}
+		piton.SetTheme(theme)
+	}
+
+	var replay *evolve.Replay
+	if *replayFile != "" {
+		replay, err = loadReplay(*replayFile)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	var config *piton.BoardConfig
+	if *levelFile != "" {
+		level, err := loadLevel(*levelFile)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		config = &level.Config
+		if level.Speed > 0 && *difficultyName == "" {
+			piton.SetSpeed(level.Speed)
+		}
+		fmt.Println("Level:", level.Name, "by", level.Author)
+	}
+	if *wrapEdges != "" {
+		wrap, err := piton.ParseWrap(*wrapEdges)
+		if err != nil {
+			fmt.Println("-wrap:", err)
+			os.Exit(1)
+		}
+		if config == nil {
+			config = &piton.BoardConfig{}
+		}
+		config.Wrap = wrap
+	}
+
 	fmt.Println("Severus start")
 
 	io.PressEnter()
@@ -41,6 +116,17 @@ func main() {
 
 	piton.Init()
 	defer piton.Close()
+	piton.SetRewind(*rewind)
+	piton.SetMinimap(*minimap)
+	book, err := loadScores(*scoresFile)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	piton.SetScoreBook(book)
+	piton.SetSaveHandler(func(g *piton.Game) error {
+		return saveGame(g, *saveFile)
+	})
 
 	for !quit {
 
@@ -48,6 +134,8 @@ func main() {
 
 		fmt.Println("Enter one of the following:")
 		fmt.Println(" p to play")
+		fmt.Println(" l to load the saved game")
+		fmt.Println(" a to let the computer play")
 		fmt.Println(" q to quit")
 		fmt.Println("")
 		fmt.Print("Choice? ")
@@ -59,12 +147,166 @@ func main() {
 		if strings.Contains(text, "q") || strings.Contains(text, "Q") {
 			quit = true
 		}
+		var status *piton.GameStatus
+		if *seed != 0 {
+			status = &piton.GameStatus{Seed: *seed}
+		}
 		if strings.Contains(text, "p") || strings.Contains(text, "P") {
-			piton.NewGame(nil)
-			score := piton.HumanPlay()
-			fmt.Print("Game over. Your score is ", score, ".  Press Enter")
-			reader.ReadString('\n')
+			if err := piton.NewGame(config, status); err != nil {
+				fmt.Print(err, ".  Press Enter")
+				reader.ReadString('\n')
+			} else {
+				play(reader, book, *scoresFile)
+			}
+		}
+		if strings.Contains(text, "a") || strings.Contains(text, "A") {
+			if replay != nil {
+				watchReplay(reader, replay)
+			} else if err := piton.NewGame(config, status); err != nil {
+				fmt.Print(err, ".  Press Enter")
+				reader.ReadString('\n')
+			} else {
+				watch(reader, status)
+			}
+		}
+		if strings.Contains(text, "l") || strings.Contains(text, "L") {
+			if err := loadGame(*saveFile); err != nil {
+				fmt.Print(err, ".  Press Enter")
+				reader.ReadString('\n')
+			} else {
+				play(reader, book, *scoresFile)
+			}
 		}
 	}
 	fmt.Println("Severus end")
 }
+
+func play(reader *bufio.Reader, book *piton.ScoreBook, scoresFile string) {
+	piton.HumanPlay()
+	if err := saveScores(book, scoresFile); err != nil {
+		fmt.Print("Best scores not saved: ", err, ".  Press Enter")
+		reader.ReadString('\n')
+	}
+}
+
+func watch(reader *bufio.Reader, status *piton.GameStatus) {
+	sequence := piton.PlayAlone(false, status)
+	piton.ClearConsole()
+	piton.OutputBoard(piton.CurrentBoard)
+	fmt.Print("The computer scored ", piton.Score(), " in ", len(sequence), " moves, seed ", piton.Seed(), ".  Press Enter")
+	reader.ReadString('\n')
+}
+
+func watchReplay(reader *bufio.Reader, replay *evolve.Replay) {
+	status := replay.Status()
+	if err := piton.NewGame(&replay.Board, &status); err != nil {
+		fmt.Print(err, ".  Press Enter")
+		reader.ReadString('\n')
+		return
+	}
+	moves := 0
+	for _, direction := range replay.Moves {
+		moves++
+		if piton.Step(direction).GameOver {
+			break
+		}
+	}
+	piton.ClearConsole()
+	piton.OutputBoard(piton.CurrentBoard)
+	fmt.Print("The replay scored ", piton.Score(), " in ", moves, " moves, ", replay.Score, " when it was recorded, seed ", replay.Seed, ".  Press Enter")
+	reader.ReadString('\n')
+}
+
+func loadReplay(fileName string) (*evolve.Replay, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	replay, err := evolve.LoadReplay(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fileName, err)
+	}
+	return replay, nil
+}
+
+func loadScores(fileName string) (*piton.ScoreBook, error) {
+	file, err := os.Open(fileName)
+	if os.IsNotExist(err) {
+		return piton.NewScoreBook(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	book, err := piton.LoadScoreBook(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fileName, err)
+	}
+	return book, nil
+}
+
+func saveScores(book *piton.ScoreBook, fileName string) error {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	if err := book.Save(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func saveGame(g *piton.Game, fileName string) error {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	if err := g.Save(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func loadGame(fileName string) error {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := piton.Load(file); err != nil {
+		return fmt.Errorf("%s: %v", fileName, err)
+	}
+	return nil
+}
+
+func loadTheme(name string) (*piton.Theme, error) {
+	file, err := os.Open(name)
+	if os.IsNotExist(err) {
+		return piton.ThemeByName(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	theme, err := piton.LoadTheme(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return theme, nil
+}
+
+func loadLevel(fileName string) (*piton.Level, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	level, err := piton.LoadLevel(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fileName, err)
+	}
+	return level, nil
+}
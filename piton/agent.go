@@ -0,0 +1,265 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package piton
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+/*
+Agent is a computer player: every move it looks at the game and chooses the direction
+the snake goes to, Right, Left, Up or Down
+*/
+type Agent interface {
+	Move(obs Observation) int
+}
+
+/*
+Observation is what an agent sees of the game, as returned by Observe. It can only be read,
+the cells go from 0 to Width+1 and from 0 to Height+1, the border being the wall
+*/
+type Observation struct {
+	g *Game
+}
+
+/*Width returns how many playable cells a row of the board has*/
+func (obs Observation) Width() int {
+	return obs.g.config.Width
+}
+
+/*Height returns how many playable rows the board has*/
+func (obs Observation) Height() int {
+	return obs.g.config.Height
+}
+
+/*Cell returns what is on the x, y cell, Wall outside the board*/
+func (obs Observation) Cell(x int, y int) int {
+	if y < 0 || y >= len(obs.g.board) || x < 0 || x >= len(obs.g.board[y]) {
+		return Wall
+	}
+	return obs.g.board[y][x]
+}
+
+/*Head returns where the head of the snake is*/
+func (obs Observation) Head() Coord {
+	return obs.g.body.headCoord()
+}
+
+/*Body returns the cells of the snake from the head to the tail*/
+func (obs Observation) Body() []Coord {
+	body := make([]Coord, obs.g.body.len())
+	for i := range body {
+		body[i] = obs.g.body.at(i)
+	}
+	return body
+}
+
+/*Fruits returns where the fruits are*/
+func (obs Observation) Fruits() []Coord {
+	return obs.g.Fruits()
+}
+
+/*Direction returns the direction the snake is going to, none when it is standing still*/
+func (obs Observation) Direction() int {
+	return obs.g.direction
+}
+
+/*Tick returns how many moves were made since the start of the game*/
+func (obs Observation) Tick() int {
+	return obs.g.moves
+}
+
+/*Score returns the current score*/
+func (obs Observation) Score() int {
+	return obs.g.score
+}
+
+/*Growth returns how many segments the snake has still to grow*/
+func (obs Observation) Growth() int {
+	return obs.g.growth
+}
+
+/*FruitGrowth returns how many segments the snake grows when eating a fruit of the kind*/
+func (obs Observation) FruitGrowth(kind int) int {
+	return obs.g.config.Fruit.rule(kind).Growth
+}
+
+/*Wrap returns the edges the snake goes through*/
+func (obs Observation) Wrap() int {
+	return obs.g.config.Wrap
+}
+
+/*Neighbor returns the cell next to c in the given direction, coming back from the opposite edge when it wraps*/
+func (obs Observation) Neighbor(c Coord, direction int) Coord {
+	return obs.g.neighbor(c, direction)
+}
+
+/*HeadNeighbor returns what is on the cell next to the head in the given direction*/
+func (obs Observation) HeadNeighbor(direction int) int {
+	return obs.g.SnakeHeadNeighbor(direction)
+}
+
+/*FruitLocation returns where the nearest fruit is seen from the x, y cell, as Game.FruitLocation does*/
+func (obs Observation) FruitLocation(x int, y int) int {
+	return obs.g.FruitLocation(x, y)
+}
+
+/*Rand returns the random source of the moves, so that games with the same seed are played the same way*/
+func (obs Observation) Rand() *rand.Rand {
+	return obs.g.moveRng
+}
+
+/*NewCoord returns the x, y cell*/
+func NewCoord(x int, y int) Coord {
+	return Coord{x, y}
+}
+
+var agents = map[string]func() Agent{}
+
+/*RegisterAgent makes an agent available by name, create returns a new agent ready to play a game*/
+func RegisterAgent(name string, create func() Agent) {
+	agents[strings.ToLower(name)] = create
+}
+
+/*AgentNames returns the names of the registered agents in alphabetical order*/
+func AgentNames() []string {
+	names := make([]string, 0, len(agents))
+	for name := range agents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+/*NewAgent creates a registered agent*/
+func NewAgent(name string) (Agent, error) {
+	create, found := agents[strings.ToLower(name)]
+	if !found {
+		return nil, fmt.Errorf("unknown agent %q, choose one of %s", name, strings.Join(AgentNames(), ", "))
+	}
+	return create(), nil
+}
+
+func init() {
+	RegisterAgent("random", func() Agent { return RandomAgent{} })
+}
+
+/*RandomAgent goes in a random direction, only never back on its neck*/
+type RandomAgent struct{}
+
+/*Move chooses a random direction that doesn't go back on the neck*/
+func (RandomAgent) Move(obs Observation) int {
+	body := obs.Body()
+	for {
+		direction := obs.Rand().Intn(4) + 1
+		if len(body) < 2 || obs.Neighbor(body[0], direction) != body[1] {
+			return direction
+		}
+	}
+}
+
+/*ReplayAgent makes the moves of a sequence, then lets Then choose*/
+type ReplayAgent struct {
+	Moves GameSequence
+	Then  Agent
+	next  int
+}
+
+/*Move returns the next move of the sequence, or the move chosen by Then when the sequence is over*/
+func (a *ReplayAgent) Move(obs Observation) int {
+	if a.next < len(a.Moves) {
+		a.next++
+		return a.Moves[a.next-1]
+	}
+	return a.Then.Move(obs)
+}
+
+/*SetAgent sets who plays the default game in PlayAlone*/
+func SetAgent(agent Agent) {
+	defaultGame.SetAgent(agent)
+}
+
+/*SetAgent sets who plays the game in PlayAlone and after the sequence in ReplayGame, nil is RandomAgent*/
+func (g *Game) SetAgent(agent Agent) {
+	if agent == nil {
+		agent = RandomAgent{}
+	}
+	g.agent = agent
+}
+
+/*
+agentMoveLimit returns how many moves an agent makes at most: the MaxMoves of the board config,
+otherwise MaxGameSequenceLength or, on bigger boards, the area of the board squared,
+enough for an agent that goes around the whole board for every fruit to fill it
+*/
+func (g *Game) agentMoveLimit() int {
+	if g.config.MaxMoves > 0 {
+		return g.config.MaxMoves
+	}
+	area := g.config.Width * g.config.Height
+	if area*area > MaxGameSequenceLength {
+		return area * area
+	}
+	return MaxGameSequenceLength
+}
+
+/*Observe returns what agents see of the default game*/
+func Observe() Observation {
+	return defaultGame.Observe()
+}
+
+/*
+Observe returns what agents see of the game, to choose the moves of a game played with Step.
+The observation shows the game as it is when it is read, it doesn't have to be taken again after every move
+*/
+func (g *Game) Observe() Observation {
+	return Observation{g}
+}
+
+/*
+playAgent lets the agent play until the game is over and returns the moves it made.
+It stops after the MaxMoves of the board config, or after agentMoveLimit moves when the board has no limit,
+and then the snake dies with DeathNoMoves
+*/
+func (g *Game) playAgent(agent Agent, verboseFlag bool, game *GameStatus) GameSequence {
+	gameOver := false
+	currentGameSequence := []int{}
+	for i := 0; !gameOver && i < g.agentMoveLimit(); i++ {
+		if verboseFlag {
+			g.renderer.Clear()
+			g.renderer.Board(g.board)
+		}
+		g.direction = agent.Move(g.Observe())
+		g.renderer.Move(g.direction)
+		g.renderer.Flush()
+		gameOver = g.snakeProceedGivenMatch(game)
+		currentGameSequence = append(currentGameSequence, g.direction)
+	}
+	if !gameOver {
+		g.snakeDies(DeathNoMoves)
+	}
+	return currentGameSequence
+}
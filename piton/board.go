@@ -0,0 +1,201 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package piton
+
+import (
+	"fmt"
+)
+
+/*
+BoardConfig describes the board of a game and where the snake starts.
+Width and Height count the playable cells only, the board has a wall all around them,
+so StartX goes from 1 to Width and StartY from 1 to Height.
+Walls, Fruits and Poisons are placed on the board before the game starts,
+the fruits in Fruits stay where they are until eaten and don't count in Fruit.Count.
+Wrap tells on which edges the snake goes through and comes back from the opposite edge.
+The player wins when the score reaches TargetScore or when there is no room left for a fruit,
+TargetScore set to NoTargetScore leaves only the second way.
+MaxMoves is how many moves the snake makes before it dies with DeathNoMoves, zero means no limit,
+as in the games of a human player; agents and optimizers set it to end games that go on forever.
+Fields left to zero take the value of DefaultBoardConfig, but on a board smaller than the default one
+the snake starts in the middle of the board
+*/
+type BoardConfig struct {
+	Width          int
+	Height         int
+	SnakeLength    int
+	StartX         int
+	StartY         int
+	StartDirection int
+	Walls          []Coord
+	Fruits         []Coord
+	Poisons        []Coord
+	Poison         PoisonConfig
+	Wrap           int
+	Fruit          FruitConfig
+	TargetScore    int
+	MaxMoves       int
+}
+
+const startingLength = 3
+
+/*DefaultBoardConfig returns the classic 20x10 board*/
+func DefaultBoardConfig() BoardConfig {
+	return BoardConfig{
+		Width:          playableBoardW,
+		Height:         playableBoardH,
+		SnakeLength:    startingLength,
+		StartX:         startingX,
+		StartY:         startingY,
+		StartDirection: none,
+		Poison:         PoisonConfig{}.withDefaults(),
+		Fruit:          defaultFruitConfig(),
+		TargetScore:    MaxGameScore,
+	}
+}
+
+/*withDefaults fills the zero fields with the default values*/
+func (c BoardConfig) withDefaults() BoardConfig {
+	def := DefaultBoardConfig()
+	if c.Width == 0 {
+		c.Width = def.Width
+	}
+	if c.Height == 0 {
+		c.Height = def.Height
+	}
+	if c.SnakeLength == 0 {
+		c.SnakeLength = def.SnakeLength
+	}
+	if c.StartX == 0 {
+		c.StartX = min(def.StartX, (c.Width+1)/2)
+	}
+	if c.StartY == 0 {
+		c.StartY = min(def.StartY, (c.Height+1)/2)
+	}
+	if c.StartDirection == 0 {
+		c.StartDirection = def.StartDirection
+	}
+	c.Poison = c.Poison.withDefaults()
+	c.Fruit = c.Fruit.withDefaults()
+	if c.TargetScore == 0 {
+		c.TargetScore = def.TargetScore
+	}
+	return c
+}
+
+/*
+startingHeading returns the direction the snake faces at the start.
+The body lies behind the head, so a snake standing still looks up with its body below
+*/
+func (c BoardConfig) startingHeading() int {
+	if c.StartDirection < Right || c.StartDirection > Down {
+		return Up
+	}
+	return c.StartDirection
+}
+
+/*Check returns an error if the snake doesn't fit on the board*/
+func (c BoardConfig) Check() error {
+	if c.Width < 1 || c.Height < 1 {
+		return fmt.Errorf("board %dx%d is too small", c.Width, c.Height)
+	}
+	if c.SnakeLength < 1 {
+		return fmt.Errorf("snake length %d is too short", c.SnakeLength)
+	}
+	if c.Wrap < 0 || c.Wrap > WrapAll {
+		return fmt.Errorf("unknown wrapping edges %d", c.Wrap)
+	}
+	if c.Fruit.Count < 0 || c.Fruit.Normal.Weight < 0 || c.Fruit.Golden.Weight < 0 || c.Fruit.Timed.Weight < 0 {
+		return fmt.Errorf("fruit count and weights can't be negative")
+	}
+	if c.TargetScore < NoTargetScore {
+		return fmt.Errorf("target score %d can't be negative, NoTargetScore turns it off", c.TargetScore)
+	}
+	if c.MaxMoves < 0 {
+		return fmt.Errorf("move limit %d can't be negative", c.MaxMoves)
+	}
+	if c.Poison.Rule < 0 || c.Poison.Rule > PoisonPenalty {
+		return fmt.Errorf("unknown poison rule %d", c.Poison.Rule)
+	}
+	taken := make(map[Coord]bool)
+	for _, cells := range [][]Coord{c.Walls, c.Fruits, c.Poisons} {
+		for _, cell := range cells {
+			if !c.isPlayable(cell) {
+				return fmt.Errorf("cell %d,%d is outside the %dx%d board", cell.x, cell.y, c.Width, c.Height)
+			}
+			taken[cell] = true
+		}
+	}
+	behind := opposite(c.startingHeading())
+	cell := Coord{c.StartX, c.StartY}
+	for i := 0; i < c.SnakeLength; i++ {
+		if !c.isPlayable(cell) || taken[cell] {
+			return fmt.Errorf("snake of length %d starting at %d,%d doesn't fit on the board",
+				c.SnakeLength, c.StartX, c.StartY)
+		}
+		cell = nextCoord(cell, behind)
+	}
+	return nil
+}
+
+func (c BoardConfig) isPlayable(cell Coord) bool {
+	return cell.x >= 1 && cell.x <= c.Width && cell.y >= 1 && cell.y <= c.Height
+}
+
+/*opposite returns the direction opposite to the given one*/
+func opposite(direction int) int {
+	switch direction {
+	case Right:
+		return Left
+	case Left:
+		return Right
+	case Up:
+		return Down
+	case Down:
+		return Up
+	}
+	return none
+}
+
+/*makeBoard returns the board described by the config, without the snake*/
+func (c BoardConfig) makeBoard() BoardType {
+	board := makeWalledBoard(c.Width, c.Height)
+	for _, wall := range c.Walls {
+		board[wall.y][wall.x] = Wall
+	}
+	return board
+}
+
+/*makeWalledBoard returns an empty board with a wall all around*/
+func makeWalledBoard(width int, height int) BoardType {
+	board := make(BoardType, height+2)
+	for y := range board {
+		board[y] = make([]int, width+2)
+		for x := range board[y] {
+			if y == 0 || y == height+1 || x == 0 || x == width+1 {
+				board[y][x] = Wall
+			}
+		}
+	}
+	return board
+}
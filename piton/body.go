@@ -0,0 +1,66 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package piton
+
+/*
+snakeBody is a ring buffer with the cells of the snake, from the head to the tail.
+Pushing a new head and popping the tail never move the other segments
+*/
+type snakeBody struct {
+	cells  []Coord
+	head   int
+	length int
+}
+
+func newSnakeBody(capacity int) snakeBody {
+	return snakeBody{cells: make([]Coord, capacity)}
+}
+
+func (b *snakeBody) len() int {
+	return b.length
+}
+
+/*at returns the i-th segment, 0 being the head*/
+func (b *snakeBody) at(i int) Coord {
+	return b.cells[(b.head+i)%len(b.cells)]
+}
+
+func (b *snakeBody) headCoord() Coord {
+	return b.at(0)
+}
+
+func (b *snakeBody) tailCoord() Coord {
+	return b.at(b.length - 1)
+}
+
+func (b *snakeBody) pushHead(c Coord) {
+	b.head = (b.head - 1 + len(b.cells)) % len(b.cells)
+	b.cells[b.head] = c
+	b.length++
+}
+
+func (b *snakeBody) popTail() Coord {
+	tail := b.tailCoord()
+	b.length--
+	return tail
+}
@@ -0,0 +1,109 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package piton
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+/*
+Difficulty sets the pace of the game: the snake moves every Tick milliseconds at the start
+and Step milliseconds faster every Every points, but never faster than every MinTick milliseconds.
+Every left to zero keeps the pace steady
+*/
+type Difficulty struct {
+	Name    string
+	Tick    int
+	MinTick int
+	Every   int
+	Step    int
+}
+
+const defaultTick = 250
+
+var difficulties = []Difficulty{
+	{Name: "easy", Tick: 350, MinTick: 200, Every: 5, Step: 10},
+	{Name: "normal", Tick: defaultTick, MinTick: 120, Every: 5, Step: 15},
+	{Name: "hard", Tick: 160, MinTick: 70, Every: 4, Step: 15},
+	{Name: "insane", Tick: 90, MinTick: 40, Every: 3, Step: 10},
+}
+
+/*DifficultyNames returns the names of the difficulty presets, from the easiest*/
+func DifficultyNames() []string {
+	names := make([]string, len(difficulties))
+	for i, d := range difficulties {
+		names[i] = d.Name
+	}
+	return names
+}
+
+/*DifficultyByName returns the difficulty preset with the given name*/
+func DifficultyByName(name string) (Difficulty, error) {
+	for _, d := range difficulties {
+		if d.Name == strings.ToLower(name) {
+			return d, nil
+		}
+	}
+	return Difficulty{}, fmt.Errorf("unknown difficulty %q, choose one of %s", name, strings.Join(DifficultyNames(), ", "))
+}
+
+/*SetDifficulty sets the pace of the default game*/
+func SetDifficulty(difficulty Difficulty) {
+	defaultGame.SetDifficulty(difficulty)
+}
+
+/*SetDifficulty sets the pace of the game*/
+func (g *Game) SetDifficulty(difficulty Difficulty) {
+	g.difficulty = difficulty
+}
+
+/*SetSpeed sets how many milliseconds the snake of the default game waits between two moves at the start*/
+func SetSpeed(milliseconds int) {
+	defaultGame.SetSpeed(milliseconds)
+}
+
+/*SetSpeed sets how many milliseconds the snake waits between two moves at the start*/
+func (g *Game) SetSpeed(milliseconds int) {
+	g.difficulty.Tick = milliseconds
+	if g.difficulty.MinTick > milliseconds {
+		g.difficulty.MinTick = milliseconds
+	}
+}
+
+/*TickDuration returns how long the snake waits between two moves with the current score*/
+func (g *Game) TickDuration() time.Duration {
+	d := g.difficulty
+	tick := d.Tick
+	if d.Every > 0 {
+		tick -= g.score / d.Every * d.Step
+	}
+	if tick < d.MinTick {
+		tick = d.MinTick
+	}
+	if tick < 1 {
+		tick = 1
+	}
+	return time.Duration(tick) * time.Millisecond
+}
@@ -0,0 +1,360 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+/*
+Package evolve looks for good games with a genetic algorithm. An individual is a GameSequence,
+all of them are played on the same board with the same seed, so the fruits always come in the same places
+and a sequence always plays the same game. The fitter a sequence, the more it scores and the longer it survives
+*/
+package evolve
+
+import (
+	"fmt"
+	"math/rand"
+	"serpent/piton"
+	"sort"
+	"time"
+)
+
+/*
+Config describes a run. Population individuals of Length moves are bred for Generations generations,
+the Elite best ones go to the next generation unchanged and the parents of the others are the winners
+of tournaments among TournamentSize individuals. CrossoverRate is the chance that a child mixes two parents,
+MutationRate the chance that each move is replaced by a random one and SegmentRate the chance that
+SegmentLength moves in a row are replaced by a random walk.
+Fitness is ScoreWeight points for every point of the score plus one for every move the snake survived.
+Board is the board, the default one when nil, GameSeed the seed of the game and Seed the seed of the algorithm,
+taken from the clock when left to zero
+*/
+type Config struct {
+	Population     int
+	Generations    int
+	Length         int
+	Elite          int
+	TournamentSize int
+	CrossoverRate  float64
+	MutationRate   float64
+	SegmentRate    float64
+	SegmentLength  int
+	ScoreWeight    int
+	Board          *piton.BoardConfig
+	GameSeed       int64
+	Seed           int64
+}
+
+/*DefaultConfig returns a config that breeds 100 sequences of 500 moves for 100 generations*/
+func DefaultConfig() Config {
+	return Config{
+		Population:     100,
+		Generations:    100,
+		Length:         500,
+		Elite:          2,
+		TournamentSize: 3,
+		CrossoverRate:  0.7,
+		MutationRate:   0.01,
+		SegmentRate:    0.3,
+		SegmentLength:  10,
+		ScoreWeight:    100,
+	}
+}
+
+/*Check tells what is wrong with the config, nil if it can be run*/
+func (c Config) Check() error {
+	switch {
+	case c.Population < 2:
+		return fmt.Errorf("the population must have at least 2 individuals, not %d", c.Population)
+	case c.Generations < 1:
+		return fmt.Errorf("there must be at least 1 generation, not %d", c.Generations)
+	case c.Length < 1 || c.Length > piton.MaxGameSequenceLength:
+		return fmt.Errorf("the length must be between 1 and %d, not %d", piton.MaxGameSequenceLength, c.Length)
+	case c.Elite < 0 || c.Elite >= c.Population:
+		return fmt.Errorf("the elite must be between 0 and %d, not %d", c.Population-1, c.Elite)
+	case c.TournamentSize < 1 || c.TournamentSize > c.Population:
+		return fmt.Errorf("the tournament size must be between 1 and %d, not %d", c.Population, c.TournamentSize)
+	case c.CrossoverRate < 0 || c.CrossoverRate > 1:
+		return fmt.Errorf("the crossover rate must be between 0 and 1, not %g", c.CrossoverRate)
+	case c.MutationRate < 0 || c.MutationRate > 1:
+		return fmt.Errorf("the mutation rate must be between 0 and 1, not %g", c.MutationRate)
+	case c.SegmentRate < 0 || c.SegmentRate > 1:
+		return fmt.Errorf("the segment rate must be between 0 and 1, not %g", c.SegmentRate)
+	case c.SegmentLength < 1:
+		return fmt.Errorf("the segment length must be at least 1, not %d", c.SegmentLength)
+	case c.ScoreWeight < 0:
+		return fmt.Errorf("the score weight can't be negative, not %d", c.ScoreWeight)
+	}
+	if c.Board != nil {
+		return piton.CreateGameWithRenderer(nil).NewGame(c.Board, nil)
+	}
+	return nil
+}
+
+/*
+Individual is a sequence of moves and how it played: Played is how many moves were made
+before the game was over, Death why the snake died, piton.DeathNone if it didn't
+*/
+type Individual struct {
+	Moves   piton.GameSequence
+	Score   int
+	Played  int
+	Death   int
+	Won     bool
+	Fitness int
+}
+
+/*
+Stats tells how a generation went: the best, mean and worst fitness, the score and the moves
+of the best individual, and how many individuals died and won
+*/
+type Stats struct {
+	Generation   int
+	BestFitness  int
+	MeanFitness  float64
+	WorstFitness int
+	BestScore    int
+	BestPlayed   int
+	Deaths       int
+	Wins         int
+}
+
+func (s Stats) String() string {
+	return fmt.Sprintf("generation %d: fitness best %d, mean %.1f, worst %d; best score %d in %d moves; %d died, %d won",
+		s.Generation, s.BestFitness, s.MeanFitness, s.WorstFitness, s.BestScore, s.BestPlayed, s.Deaths, s.Wins)
+}
+
+/*Evolver runs the genetic algorithm one generation at a time*/
+type Evolver struct {
+	config     Config
+	board      piton.BoardConfig
+	status     piton.GameStatus
+	game       *piton.Game
+	rng        *rand.Rand
+	population []Individual
+	generation int
+}
+
+/*New creates an evolver with a random first generation*/
+func New(config Config) (*Evolver, error) {
+	if err := config.Check(); err != nil {
+		return nil, err
+	}
+	if config.Seed == 0 {
+		config.Seed = time.Now().UnixNano()
+	}
+	if config.GameSeed == 0 {
+		config.GameSeed = time.Now().UnixNano()
+	}
+	e := &Evolver{config: config, rng: rand.New(rand.NewSource(config.Seed))}
+	e.game = piton.CreateGameWithRenderer(nil)
+	if err := e.game.NewGame(config.Board, nil); err != nil {
+		return nil, err
+	}
+	e.board = e.game.Config()
+	e.status = e.game.GenerateGameParamsFromSeed(config.GameSeed)
+	e.population = make([]Individual, config.Population)
+	for i := range e.population {
+		moves := make(piton.GameSequence, config.Length)
+		e.randomWalk(moves)
+		e.population[i] = e.Evaluate(moves)
+	}
+	e.sortPopulation()
+	return e, nil
+}
+
+/*Evaluate plays the moves on the board of the evolver and tells how they played*/
+func (e *Evolver) Evaluate(moves piton.GameSequence) Individual {
+	e.game.NewGame(&e.board, &e.status)
+	played := 0
+	for _, direction := range moves {
+		played++
+		if e.game.Step(direction).GameOver {
+			break
+		}
+	}
+	ind := Individual{Moves: moves, Score: e.game.Score(), Played: played, Death: e.game.DeathCause(), Won: e.game.Won()}
+	survived := played
+	if ind.Won {
+		survived = len(moves)
+	}
+	ind.Fitness = ind.Score*e.config.ScoreWeight + survived
+	return ind
+}
+
+/*Step breeds the next generation and returns its stats*/
+func (e *Evolver) Step() Stats {
+	next := make([]Individual, 0, len(e.population))
+	next = append(next, e.population[:e.config.Elite]...)
+	for len(next) < len(e.population) {
+		first, second := e.tournament(), e.tournament()
+		var moves piton.GameSequence
+		if e.rng.Float64() < e.config.CrossoverRate {
+			moves = e.crossover(first, second)
+		} else {
+			moves = append(piton.GameSequence{}, first.Moves...)
+		}
+		e.mutate(moves, first.Played)
+		next = append(next, e.Evaluate(moves))
+	}
+	e.population = next
+	e.sortPopulation()
+	e.generation++
+	return e.Stats()
+}
+
+/*Run breeds all the generations of the config, tells report the stats of each one and returns the best individual*/
+func (e *Evolver) Run(report func(Stats)) Individual {
+	for e.generation < e.config.Generations {
+		stats := e.Step()
+		if report != nil {
+			report(stats)
+		}
+	}
+	return e.Best()
+}
+
+/*Best returns the fittest individual of the current generation*/
+func (e *Evolver) Best() Individual {
+	return e.population[0]
+}
+
+/*Generation returns how many generations were bred, 0 for the random first one*/
+func (e *Evolver) Generation() int {
+	return e.generation
+}
+
+/*Stats returns the stats of the current generation*/
+func (e *Evolver) Stats() Stats {
+	best, worst := e.population[0], e.population[len(e.population)-1]
+	stats := Stats{Generation: e.generation, BestFitness: best.Fitness, WorstFitness: worst.Fitness,
+		BestScore: best.Score, BestPlayed: best.Played}
+	total := 0
+	for _, ind := range e.population {
+		total += ind.Fitness
+		if ind.Death != piton.DeathNone {
+			stats.Deaths++
+		}
+		if ind.Won {
+			stats.Wins++
+		}
+	}
+	stats.MeanFitness = float64(total) / float64(len(e.population))
+	return stats
+}
+
+/*Replay returns the game of the best individual, only the moves that were played*/
+func (e *Evolver) Replay() *Replay {
+	best := e.Best()
+	return &Replay{
+		Version: replayVersion,
+		Board:   e.board,
+		Seed:    e.config.GameSeed,
+		Moves:   append(piton.GameSequence{}, best.Moves[:best.Played]...),
+		Score:   best.Score,
+	}
+}
+
+/*sortPopulation puts the fittest individuals first, the ones that played fewer moves first among equals*/
+func (e *Evolver) sortPopulation() {
+	sort.SliceStable(e.population, func(i, j int) bool {
+		if e.population[i].Fitness != e.population[j].Fitness {
+			return e.population[i].Fitness > e.population[j].Fitness
+		}
+		return e.population[i].Played < e.population[j].Played
+	})
+}
+
+/*tournament returns the fittest of TournamentSize individuals taken at random*/
+func (e *Evolver) tournament() Individual {
+	best := e.rng.Intn(len(e.population))
+	for i := 1; i < e.config.TournamentSize; i++ {
+		if other := e.rng.Intn(len(e.population)); other < best {
+			best = other
+		}
+	}
+	return e.population[best]
+}
+
+/*
+crossover returns the moves of the fitter parent up to a random cut, then those of the other parent.
+The cut comes before the game of the fitter parent was over, so the child plays the same game up to there
+*/
+func (e *Evolver) crossover(first Individual, second Individual) piton.GameSequence {
+	if second.Fitness > first.Fitness {
+		first, second = second, first
+	}
+	cut := e.rng.Intn(first.Played)
+	moves := append(piton.GameSequence{}, first.Moves[:cut]...)
+	return append(moves, second.Moves[cut:]...)
+}
+
+/*
+mutate replaces every move with a random one with MutationRate chance,
+then with SegmentRate chance replaces SegmentLength moves with a random walk
+that starts a little before where the game of the parent was over
+*/
+func (e *Evolver) mutate(moves piton.GameSequence, played int) {
+	for i := range moves {
+		if e.rng.Float64() < e.config.MutationRate {
+			moves[i] = piton.Right + e.rng.Intn(4)
+		}
+	}
+	if e.rng.Float64() < e.config.SegmentRate {
+		start := played - 1 - e.rng.Intn(e.config.SegmentLength)
+		if start < 0 {
+			start = 0
+		}
+		end := start + e.config.SegmentLength
+		if end > len(moves) {
+			end = len(moves)
+		}
+		e.randomWalk(moves[start:end])
+		if start > 0 && moves[start] == opposite(moves[start-1]) {
+			moves[start] = moves[start-1]
+		}
+	}
+}
+
+/*randomWalk fills the moves with random directions that never go back where the previous one came from*/
+func (e *Evolver) randomWalk(moves piton.GameSequence) {
+	for i := range moves {
+		for {
+			moves[i] = piton.Right + e.rng.Intn(4)
+			if i == 0 || moves[i] != opposite(moves[i-1]) {
+				break
+			}
+		}
+	}
+}
+
+/*opposite returns the direction that goes back*/
+func opposite(direction int) int {
+	switch direction {
+	case piton.Right:
+		return piton.Left
+	case piton.Left:
+		return piton.Right
+	case piton.Up:
+		return piton.Down
+	case piton.Down:
+		return piton.Up
+	}
+	return direction
+}
@@ -0,0 +1,80 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package evolve
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"serpent/piton"
+)
+
+/*replayVersion is the version of the format of the replays*/
+const replayVersion = 1
+
+/*
+Replay is a game that can be played again: the board, the seed that places the fruits and the moves.
+Score is the score the moves made when the replay was written
+*/
+type Replay struct {
+	Version int
+	Board   piton.BoardConfig
+	Seed    int64
+	Moves   piton.GameSequence
+	Score   int
+}
+
+/*Save writes the replay as JSON*/
+func (r *Replay) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(r)
+}
+
+/*LoadReplay reads a replay written by Save*/
+func LoadReplay(r io.Reader) (*Replay, error) {
+	replay := &Replay{}
+	if err := json.NewDecoder(r).Decode(replay); err != nil {
+		return nil, err
+	}
+	if replay.Version != replayVersion {
+		return nil, fmt.Errorf("unknown replay version %d", replay.Version)
+	}
+	if replay.Seed == 0 {
+		return nil, fmt.Errorf("the replay has no seed")
+	}
+	if err := replay.Board.Check(); err != nil {
+		return nil, err
+	}
+	for i, direction := range replay.Moves {
+		if direction < piton.Right || direction > piton.Down {
+			return nil, fmt.Errorf("move %d is not a direction: %d", i+1, direction)
+		}
+	}
+	return replay, nil
+}
+
+/*Status returns the fruits of the game of the replay, to start it with NewGame and play the moves with Step*/
+func (r *Replay) Status() piton.GameStatus {
+	g := piton.CreateGameWithRenderer(nil)
+	g.NewGame(&r.Board, nil)
+	return g.GenerateGameParamsFromSeed(r.Seed)
+}
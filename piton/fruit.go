@@ -0,0 +1,268 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package piton
+
+/*
+FruitRule describes a kind of fruit: the points it is worth, how many segments the snake grows
+when eating it, after how many moves it disappears (0 is never) and how often it appears
+compared to the other kinds
+*/
+type FruitRule struct {
+	Value    int
+	Growth   int
+	Lifetime int
+	Weight   int
+}
+
+/*
+FruitConfig tells how many fruits are on the board at the same time and what every kind of fruit does.
+A new random fruit is Fruit, GoldenFruit or TimedFruit with a probability proportional to the Weight of its rule.
+Count, Value, Growth and Lifetime left to zero take the values of the default config,
+where only normal fruits appear
+*/
+type FruitConfig struct {
+	Count  int
+	Normal FruitRule
+	Golden FruitRule
+	Timed  FruitRule
+}
+
+type fruit struct {
+	at      Coord
+	kind    int
+	expires int
+	fixed   bool
+}
+
+type scheduledFruit struct {
+	at   Coord
+	kind int
+}
+
+func defaultFruitConfig() FruitConfig {
+	return FruitConfig{
+		Count:  1,
+		Normal: FruitRule{Value: 1, Growth: 1, Weight: 1},
+		Golden: FruitRule{Value: 5, Growth: 1},
+		Timed:  FruitRule{Value: 3, Growth: 1, Lifetime: 40},
+	}
+}
+
+func (r FruitRule) withDefaults(def FruitRule) FruitRule {
+	if r.Value == 0 {
+		r.Value = def.Value
+	}
+	if r.Growth == 0 {
+		r.Growth = def.Growth
+	}
+	if r.Lifetime == 0 {
+		r.Lifetime = def.Lifetime
+	}
+	return r
+}
+
+func (c FruitConfig) withDefaults() FruitConfig {
+	def := defaultFruitConfig()
+	if c.Count == 0 {
+		c.Count = def.Count
+	}
+	if c.Normal.Weight == 0 && c.Golden.Weight == 0 && c.Timed.Weight == 0 {
+		c.Normal.Weight = def.Normal.Weight
+	}
+	c.Normal = c.Normal.withDefaults(def.Normal)
+	c.Golden = c.Golden.withDefaults(def.Golden)
+	c.Timed = c.Timed.withDefaults(def.Timed)
+	return c
+}
+
+/*rule returns the rule for the kind of fruit*/
+func (c FruitConfig) rule(kind int) FruitRule {
+	switch kind {
+	case GoldenFruit:
+		return c.Golden
+	case TimedFruit:
+		return c.Timed
+	}
+	return c.Normal
+}
+
+/*ScheduleFruit adds a fruit of the given kind on the x, y cell to the fruits that appear, in order, during the game*/
+func (status *GameStatus) ScheduleFruit(x int, y int, kind int) {
+	if !IsFruit(kind) {
+		kind = Fruit
+	}
+	status.fruits = append(status.fruits, scheduledFruit{Coord{x, y}, kind})
+}
+
+/*Fruits returns where the fruits on the board are*/
+func (g *Game) Fruits() []Coord {
+	fruits := make([]Coord, len(g.fruits))
+	for i, f := range g.fruits {
+		fruits[i] = f.at
+	}
+	return fruits
+}
+
+/*NearestFruit returns the fruit closest to x, y, or -1, -1 when there are no fruits*/
+func (g *Game) NearestFruit(x int, y int) (int, int) {
+	nearestX, nearestY := -1, -1
+	best := -1
+	for _, f := range g.fruits {
+		fx, fy := g.nearestImage(f.at.x, f.at.y, x, y)
+		distance := abs(fx-x) + abs(fy-y)
+		if best == -1 || distance < best {
+			best = distance
+			nearestX, nearestY = f.at.x, f.at.y
+		}
+	}
+	return nearestX, nearestY
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func (g *Game) initFruit(status *GameStatus) {
+	g.fruits = g.fruits[:0]
+	g.growth = 0
+	for _, f := range g.config.Fruits {
+		g.board[f.y][f.x] = Fruit
+		g.fruits = append(g.fruits, fruit{at: f, kind: Fruit, fixed: true})
+	}
+	for _, poison := range g.config.Poisons {
+		g.board[poison.y][poison.x] = Poison
+	}
+	g.poisonCount = len(g.config.Poisons)
+	g.currentFruitIndex = 0
+	g.refillFruits(status)
+}
+
+/*randomFruitKind chooses the kind of a new random fruit*/
+func (g *Game) randomFruitKind() int {
+	rules := g.config.Fruit
+	if rules.Golden.Weight == 0 && rules.Timed.Weight == 0 {
+		return Fruit
+	}
+	n := g.rng.Intn(rules.Normal.Weight + rules.Golden.Weight + rules.Timed.Weight)
+	if n < rules.Normal.Weight {
+		return Fruit
+	}
+	if n < rules.Normal.Weight+rules.Golden.Weight {
+		return GoldenFruit
+	}
+	return TimedFruit
+}
+
+/*getNextFruit returns the next scheduled fruit that falls on an empty cell*/
+func (g *Game) getNextFruit(status *GameStatus) (Coord, int, bool) {
+	for g.currentFruitIndex < len(status.fruits) {
+		next := status.fruits[g.currentFruitIndex]
+		g.currentFruitIndex++
+		if g.config.isPlayable(next.at) && isEmpty(&g.board, next.at.x, next.at.y) {
+			return next.at, next.kind, true
+		}
+	}
+	return Coord{}, none, false
+}
+
+/*respawnFruit puts a new fruit on the board, the next scheduled one if there is one left*/
+func (g *Game) respawnFruit(status *GameStatus) {
+	var at Coord
+	var kind int
+	found := false
+	if status != nil {
+		at, kind, found = g.getNextFruit(status)
+	}
+	if !found {
+		at.x, at.y = g.getNewFruitCoords(&g.board)
+		if at.x == -1 || at.y == -1 {
+			return
+		}
+		kind = g.randomFruitKind()
+	}
+	f := fruit{at: at, kind: kind}
+	if lifetime := g.config.Fruit.rule(kind).Lifetime; lifetime > 0 {
+		f.expires = g.moves + lifetime
+	}
+	g.board[at.y][at.x] = kind
+	g.fruits = append(g.fruits, f)
+}
+
+/*refillFruits puts new fruits on the board until there are as many as the config asks*/
+func (g *Game) refillFruits(status *GameStatus) {
+	for g.respawnableFruits() < g.config.Fruit.Count {
+		before := len(g.fruits)
+		g.respawnFruit(status)
+		if len(g.fruits) == before {
+			return
+		}
+	}
+}
+
+func (g *Game) respawnableFruits() int {
+	count := 0
+	for _, f := range g.fruits {
+		if !f.fixed {
+			count++
+		}
+	}
+	return count
+}
+
+/*eatFruit takes away the fruit at the given cell and gives its points and growth to the snake*/
+func (g *Game) eatFruit(at Coord) {
+	for i, f := range g.fruits {
+		if f.at == at {
+			rule := g.config.Fruit.rule(f.kind)
+			g.score += rule.Value
+			g.growth += rule.Growth
+			g.fruits = append(g.fruits[:i], g.fruits[i+1:]...)
+			return
+		}
+	}
+}
+
+/*expireFruits takes away the timed fruits that lasted long enough*/
+func (g *Game) expireFruits() {
+	kept := g.fruits[:0]
+	for _, f := range g.fruits {
+		if f.expires > 0 && g.moves >= f.expires {
+			g.board[f.at.y][f.at.x] = Empty
+			continue
+		}
+		kept = append(kept, f)
+	}
+	g.fruits = kept
+}
+
+/*takeGrowth returns true if the snake has still to grow by one segment*/
+func (g *Game) takeGrowth() bool {
+	if g.growth > 0 {
+		g.growth--
+		return true
+	}
+	return false
+}
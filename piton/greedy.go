@@ -0,0 +1,66 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package piton
+
+/*
+GreedyAgent goes straight for the nearest fruit: it takes one of the directions towards the quadrant
+of the fruit, or any other direction when those lead to a danger, and keeps going when all of them do.
+It looks only one cell ahead, so it is the baseline the other agents are measured against
+*/
+type GreedyAgent struct{}
+
+func init() {
+	RegisterAgent("greedy", func() Agent { return GreedyAgent{} })
+}
+
+/*Move chooses the first safe direction towards the nearest fruit*/
+func (GreedyAgent) Move(obs Observation) int {
+	head := obs.Head()
+	candidates := append(towardsLocation(obs.FruitLocation(head.X(), head.Y())), obs.Direction(), Right, Left, Up, Down)
+	for _, direction := range candidates {
+		if direction >= Right && direction <= Down && !IsDanger(obs.HeadNeighbor(direction)) {
+			return direction
+		}
+	}
+	if obs.Direction() >= Right && obs.Direction() <= Down {
+		return obs.Direction()
+	}
+	return Up
+}
+
+/*towardsLocation returns the directions that get closer to a location returned by FruitLocation*/
+func towardsLocation(where int) []int {
+	switch where {
+	case Right, Left, Up, Down:
+		return []int{where}
+	case Q1:
+		return []int{Up, Right}
+	case Q2:
+		return []int{Up, Left}
+	case Q3:
+		return []int{Down, Left}
+	case Q4:
+		return []int{Down, Right}
+	}
+	return nil
+}
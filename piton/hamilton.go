@@ -0,0 +1,261 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package piton
+
+/*
+HamiltonAgent follows a cycle that goes once through every cell of the board, so the snake
+can never close itself in and fills the whole board. While the snake is shorter than half the board
+it takes shortcuts towards the fruit that skip part of the cycle, but only as long as half the cycle
+stays free ahead of the head, besides the growth of the fruits on the way.
+The cycle is built through the body of the snake as it starts, on any board without walls inside
+and with an even number of cells; when there is no such cycle the agent plays like PathAgent.
+On such boards it fills the whole board whatever its size, given enough moves: less than the area
+of the board squared, which is what PlayAlone allows on boards without MaxMoves.
+Poisons are not avoided
+*/
+type HamiltonAgent struct {
+	cycle    *hamiltonCycle
+	fallback PathAgent
+}
+
+/*hamiltonCycle gives every cell its place along the cycle and the direction of the next cell*/
+type hamiltonCycle struct {
+	order  [][]int
+	next   [][]int
+	length int
+}
+
+/*shortcutMargin is how many free cells a shortcut leaves before the tail besides half the cycle and the growth still to come*/
+const shortcutMargin = 3
+
+func init() {
+	RegisterAgent("hamilton", func() Agent { return &HamiltonAgent{} })
+}
+
+/*Move follows the cycle, taking a shortcut to the fruit when it is safe*/
+func (a *HamiltonAgent) Move(obs Observation) int {
+	if obs.Tick() == 0 || a.cycle == nil || len(a.cycle.order) != obs.Height()+2 || len(a.cycle.order[0]) != obs.Width()+2 {
+		a.cycle = newHamiltonCycle(obs)
+	}
+	if a.cycle == nil {
+		return a.fallback.Move(obs)
+	}
+	body := obs.Body()
+	head, tail := body[0], body[len(body)-1]
+	direction := a.cycle.next[head.y][head.x]
+	if len(body)+obs.Growth() >= a.cycle.length/2 {
+		return direction
+	}
+	fruit, found := a.nearestFruit(obs, head)
+	if !found {
+		return direction
+	}
+	toFruit := a.cycle.distance(head, fruit)
+	room := a.cycle.distance(head, tail) - a.cycle.length/2 - obs.Growth() - a.growthAhead(obs, head, tail) - shortcutMargin
+	best := a.cycle.distance(obs.Neighbor(head, direction), fruit)
+	for d := Right; d <= Down; d++ {
+		next := obs.Neighbor(head, d)
+		cell := obs.Cell(next.x, next.y)
+		if cell != Empty && !IsFruit(cell) {
+			continue
+		}
+		skip := a.cycle.distance(head, next)
+		if skip == 0 || skip > toFruit || skip >= room {
+			continue
+		}
+		if left := a.cycle.distance(next, fruit); left < best {
+			direction, best = d, left
+		}
+	}
+	return direction
+}
+
+/*
+growthAhead returns the growth of every fruit the snake eats along the cycle before it gets where its tail is,
+twice, because the fruit that takes the place of one eaten may appear ahead too
+*/
+func (a *HamiltonAgent) growthAhead(obs Observation, head Coord, tail Coord) int {
+	growth := 0
+	ahead := a.cycle.distance(head, tail)
+	for _, fruit := range obs.Fruits() {
+		if a.cycle.distance(head, fruit) < ahead {
+			growth += 2 * obs.FruitGrowth(obs.Cell(fruit.x, fruit.y))
+		}
+	}
+	return growth
+}
+
+/*nearestFruit returns the fruit that comes first along the cycle*/
+func (a *HamiltonAgent) nearestFruit(obs Observation, head Coord) (Coord, bool) {
+	var nearest Coord
+	best := -1
+	for _, fruit := range obs.Fruits() {
+		if d := a.cycle.distance(head, fruit); best == -1 || d < best {
+			nearest, best = fruit, d
+		}
+	}
+	return nearest, best != -1
+}
+
+/*distance returns how many moves along the cycle go from a to b*/
+func (c *hamiltonCycle) distance(a Coord, b Coord) int {
+	return (c.order[b.y][b.x] - c.order[a.y][a.x] + c.length) % c.length
+}
+
+/*
+newHamiltonCycle builds a cycle the body of the snake lies along, trying every comb of the board
+turned every way and walked both ways. It returns nil if there is none
+*/
+func newHamiltonCycle(obs Observation) *hamiltonCycle {
+	w, h := obs.Width(), obs.Height()
+	for y := 1; y <= h; y++ {
+		for x := 1; x <= w; x++ {
+			if obs.Cell(x, y) == Wall {
+				return nil
+			}
+		}
+	}
+	body := obs.Body()
+	for _, transposed := range []bool{false, true} {
+		cw, ch := w, h
+		if transposed {
+			cw, ch = h, w
+		}
+		for columns := 1; columns <= cw; columns++ {
+			cells := comb(cw, ch, columns)
+			if cells == nil {
+				continue
+			}
+			for turn := 0; turn < 8; turn++ {
+				cycle := cycleThrough(arrange(cells, cw, ch, transposed, turn), w, h)
+				if cycle.follows(body) {
+					return cycle
+				}
+			}
+		}
+	}
+	return nil
+}
+
+/*
+comb returns the cells of a w x h board in the order of a cycle: the first columns up and down in turn
+from the second row, the rest of the rows, from the last one up, right and left in turn, then back along
+the first row. It returns nil when those don't close into a cycle, that is unless all the columns are walked
+and they are even, or an odd number of them are walked and the rows are even
+*/
+func comb(w int, h int, columns int) []Coord {
+	if w < 2 || h < 2 || columns == w && w%2 != 0 || columns < w && (columns%2 == 0 || h%2 != 0) {
+		return nil
+	}
+	cells := make([]Coord, 0, w*h)
+	for x := 1; x <= columns; x++ {
+		for i := 2; i <= h; i++ {
+			y := i
+			if x%2 == 0 {
+				y = h + 2 - i
+			}
+			cells = append(cells, Coord{x, y})
+		}
+	}
+	for y := h; y >= 2; y-- {
+		for i := columns + 1; i <= w; i++ {
+			x := i
+			if (h-y)%2 == 1 {
+				x = w + columns + 1 - i
+			}
+			cells = append(cells, Coord{x, y})
+		}
+	}
+	for x := w; x >= 1; x-- {
+		cells = append(cells, Coord{x, 1})
+	}
+	return cells
+}
+
+/*
+arrange turns the cells of a cycle on a w x h board: the bits of turn mirror them left to right,
+mirror them top to bottom and walk them the other way, then transposed swaps rows and columns
+*/
+func arrange(cells []Coord, w int, h int, transposed bool, turn int) []Coord {
+	arranged := make([]Coord, len(cells))
+	for i, c := range cells {
+		if turn&1 != 0 {
+			c.x = w + 1 - c.x
+		}
+		if turn&2 != 0 {
+			c.y = h + 1 - c.y
+		}
+		if transposed {
+			c.x, c.y = c.y, c.x
+		}
+		arranged[i] = c
+	}
+	if turn&4 != 0 {
+		arranged = reverseCoords(arranged)
+	}
+	return arranged
+}
+
+func reverseCoords(cells []Coord) []Coord {
+	reversed := make([]Coord, len(cells))
+	for i, c := range cells {
+		reversed[len(cells)-1-i] = c
+	}
+	return reversed
+}
+
+/*cycleThrough makes a cycle of cells given in order, every one next to the following one*/
+func cycleThrough(cells []Coord, w int, h int) *hamiltonCycle {
+	c := &hamiltonCycle{length: len(cells)}
+	c.order = make([][]int, h+2)
+	c.next = make([][]int, h+2)
+	for y := range c.order {
+		c.order[y] = make([]int, w+2)
+		c.next[y] = make([]int, w+2)
+	}
+	for i, cell := range cells {
+		next := cells[(i+1)%len(cells)]
+		c.order[cell.y][cell.x] = i
+		switch {
+		case next.x > cell.x:
+			c.next[cell.y][cell.x] = Right
+		case next.x < cell.x:
+			c.next[cell.y][cell.x] = Left
+		case next.y < cell.y:
+			c.next[cell.y][cell.x] = Up
+		default:
+			c.next[cell.y][cell.x] = Down
+		}
+	}
+	return c
+}
+
+/*follows tells if every segment of the body comes right after the next one along the cycle*/
+func (c *hamiltonCycle) follows(body []Coord) bool {
+	for i := 0; i+1 < len(body); i++ {
+		if c.distance(body[i+1], body[i]) != 1 {
+			return false
+		}
+	}
+	return true
+}
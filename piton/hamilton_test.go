@@ -0,0 +1,44 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package piton
+
+import "testing"
+
+func TestHamiltonAgentWinsFromEveryStart(t *testing.T) {
+	fruits := []FruitConfig{{}, {Count: 3, Normal: FruitRule{Growth: 3}}}
+	for _, size := range []Coord{{6, 6}, {7, 6}, {6, 7}, {10, 6}, {8, 5}, {9, 8}, {20, 10}} {
+		for _, direction := range []int{none, Right, Left, Up, Down} {
+			for i, fruit := range fruits {
+				for seed := int64(1); seed <= 6; seed++ {
+					area := size.x * size.y
+					config := BoardConfig{Width: size.x, Height: size.y, StartDirection: direction, Fruit: fruit, MaxMoves: area * area}
+					g := playSeeded(t, &HamiltonAgent{}, config, seed)
+					if !g.Won() {
+						t.Errorf("%dx%d direction %d fruits %d seed %d: game over with death cause %d at length %d of %d",
+							size.x, size.y, direction, i, seed, g.DeathCause(), g.Length(), area)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,183 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package piton
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	term "github.com/nsf/termbox-go"
+)
+
+/*hudWidth is how many columns the status panel takes*/
+const hudWidth = 24
+
+/*stopwatch measures how long the game was played, leaving out the pauses*/
+type stopwatch struct {
+	total   time.Duration
+	since   time.Time
+	running bool
+}
+
+func (s *stopwatch) start() {
+	if !s.running {
+		s.since = time.Now()
+		s.running = true
+	}
+}
+
+func (s *stopwatch) stop() {
+	if s.running {
+		s.total += time.Since(s.since)
+		s.running = false
+	}
+}
+
+func (s *stopwatch) elapsed() time.Duration {
+	if s.running {
+		return s.total + time.Since(s.since)
+	}
+	return s.total
+}
+
+/*formatDuration writes a duration as minutes and seconds*/
+func formatDuration(d time.Duration) string {
+	seconds := int(d / time.Second)
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
+
+/*mode returns the difficulty and the modifiers of the game*/
+func (g *Game) mode() string {
+	modifiers := []string{g.difficulty.Name}
+	if g.config.Wrap != 0 {
+		modifiers = append(modifiers, "wrap")
+	}
+	if g.config.Poison.Chance > 0 || len(g.config.Poisons) > 0 {
+		modifiers = append(modifiers, "poison")
+	}
+	if g.config.Fruit.Golden.Weight > 0 || g.config.Fruit.Timed.Weight > 0 {
+		modifiers = append(modifiers, "bonus")
+	}
+	if g.rewindTicks > 0 {
+		modifiers = append(modifiers, "rewind")
+	}
+	if g.practice {
+		modifiers = append(modifiers, "practice")
+	}
+	return strings.Join(modifiers, " ")
+}
+
+/*hudLines returns what the status panel shows*/
+func (g *Game) hudLines(clock *stopwatch, best int) []string {
+	return []string{
+		fmt.Sprintf("Score  %d", g.score),
+		fmt.Sprintf("Length %d", g.body.len()),
+		fmt.Sprintf("Speed  %d ms", g.TickDuration()/time.Millisecond),
+		fmt.Sprintf("Time   %s", formatDuration(clock.elapsed())),
+		fmt.Sprintf("Moves  %d", g.moves),
+		fmt.Sprintf("Best   %d", best),
+		fmt.Sprintf("Mode   %s", g.mode()),
+	}
+}
+
+/*drawHUD draws the status panel from x, y, the lines that don't fit in the terminal are cut*/
+func drawHUD(x int, y int, lines []string) {
+	width, height := term.Size()
+	for i, line := range lines {
+		if y+i >= height || x >= width {
+			return
+		}
+		text := []rune(fmt.Sprintf("%-*s", hudWidth, line))
+		if len(text) > width-x {
+			text = text[:width-x]
+		}
+		drawText(x, y+i, string(text), term.ColorDefault, term.ColorDefault)
+	}
+}
+
+/*deathText tells how the snake died*/
+func deathText(cause int) string {
+	switch cause {
+	case DeathWall:
+		return "The snake hit a wall"
+	case DeathBody:
+		return "The snake bit itself"
+	case DeathPoison:
+		return "The snake ate a poison"
+	case DeathNoMoves:
+		return "The snake ran out of moves"
+	}
+	return "The game was left"
+}
+
+/*winText tells why the player won: the score reached the target or no fruit was left*/
+func (g *Game) winText() string {
+	if g.reachedTarget() {
+		return "Target score reached"
+	}
+	return "Nothing left to eat"
+}
+
+/*
+showSummary shows how the game went until the player goes on with Enter or Esc.
+When canRewind is true Backspace rewinds the game instead, and showSummary returns true
+*/
+func (g *Game) showSummary(keyboard chan term.Event, screen *TermRenderer, clock *stopwatch, best int, newBest bool, canRewind bool) bool {
+	title := "GAME OVER"
+	if g.won {
+		title = "YOU WON"
+	}
+	score := fmt.Sprintf("Score  %d", g.score)
+	if newBest {
+		score += "  new best!"
+	}
+	lines := []string{title, deathText(g.death), "", score,
+		fmt.Sprintf("Length %d", g.body.len()),
+		fmt.Sprintf("Moves  %d", g.moves),
+		fmt.Sprintf("Time   %s", formatDuration(clock.elapsed())),
+		fmt.Sprintf("Best   %d", best),
+		fmt.Sprintf("Seed   %d", g.seed),
+		""}
+	if g.won {
+		lines[1] = g.winText()
+	}
+	if g.practice {
+		lines = append(lines, "Practice game, not recorded")
+	}
+	if canRewind {
+		lines = append(lines, "Backspace to rewind")
+	}
+	lines = append(lines, "Enter to go on")
+	for {
+		g.drawOverlay(screen, lines, -1)
+		switch keyFromEvent(<-keyboard) {
+		case backspace:
+			if canRewind {
+				return true
+			}
+		case enter, Esc:
+			return false
+		}
+	}
+}
@@ -0,0 +1,204 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package piton
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+/*
+A level file is plain text. It may start with a header of "key: value" lines
+(name, author, speed in milliseconds per move, length of the snake,
+wrap with the edges the snake goes through as read by ParseWrap) ended by an empty line.
+Lines starting with ';' in the header are comments.
+The rest of the file is the map, one line per row of playable cells:
+
+	#     wall
+	.     empty cell, a space is empty too
+	^ v < >  head of the snake, facing up, down, left or right
+	S     head of the snake, standing still
+	F     fruit that stays there until eaten
+	P     poison
+
+The body of the snake lies behind the head. Shorter rows are filled with empty cells
+and the whole map is surrounded by a wall.
+*/
+
+/*Level is a board loaded from a level file*/
+type Level struct {
+	Name   string
+	Author string
+	Speed  int
+	Config BoardConfig
+}
+
+/*LevelError is a parse error at a given line and column of a level file*/
+type LevelError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *LevelError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+func levelErrorf(line int, column int, format string, args ...interface{}) *LevelError {
+	return &LevelError{Line: line, Column: column, Msg: fmt.Sprintf(format, args...)}
+}
+
+/*LoadLevel reads a level file*/
+func LoadLevel(r io.Reader) (*Level, error) {
+	level := &Level{}
+	level.Config.SnakeLength = startingLength
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	first := 0
+	if len(lines) > 0 && isHeaderLine(lines[0]) {
+		for first < len(lines) && lines[first] != "" {
+			if err := level.parseHeaderLine(lines[first], first+1); err != nil {
+				return nil, err
+			}
+			first++
+		}
+		first++
+	}
+	if err := level.parseMap(lines, first); err != nil {
+		return nil, err
+	}
+	return level, nil
+}
+
+func isHeaderLine(line string) bool {
+	return strings.HasPrefix(line, ";") || strings.Contains(line, ":")
+}
+
+func (level *Level) parseHeaderLine(line string, lineNumber int) error {
+	if strings.HasPrefix(line, ";") {
+		return nil
+	}
+	colon := strings.Index(line, ":")
+	if colon < 0 {
+		return levelErrorf(lineNumber, 1, "expected \"key: value\" in the header")
+	}
+	key := strings.ToLower(strings.TrimSpace(line[:colon]))
+	value := strings.TrimSpace(line[colon+1:])
+	valueColumn := colon + 2 + len(line[colon+1:]) - len(strings.TrimLeft(line[colon+1:], " \t"))
+	switch key {
+	case "name":
+		level.Name = value
+	case "author":
+		level.Author = value
+	case "wrap":
+		wrap, err := ParseWrap(value)
+		if err != nil {
+			return levelErrorf(lineNumber, valueColumn, "%v", err)
+		}
+		level.Config.Wrap = wrap
+	case "speed", "length":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return levelErrorf(lineNumber, valueColumn, "%s must be a positive number, not %q", key, value)
+		}
+		if key == "speed" {
+			level.Speed = n
+		} else {
+			level.Config.SnakeLength = n
+		}
+	default:
+		return levelErrorf(lineNumber, 1, "unknown header key %q", key)
+	}
+	return nil
+}
+
+func (level *Level) parseMap(lines []string, first int) error {
+	for len(lines) > first && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if first >= len(lines) {
+		return levelErrorf(first+1, 1, "the level has no map")
+	}
+	config := &level.Config
+	spawnLine, spawnColumn := 0, 0
+	for y, line := range lines[first:] {
+		lineNumber := first + y + 1
+		for x, symbol := range []rune(line) {
+			cell := Coord{x + 1, y + 1}
+			switch symbol {
+			case '#':
+				config.Walls = append(config.Walls, cell)
+			case '.', ' ':
+			case '^', 'v', '<', '>', 'S':
+				if spawnLine != 0 {
+					return levelErrorf(lineNumber, x+1, "second snake, the first one is at line %d, column %d",
+						spawnLine, spawnColumn)
+				}
+				spawnLine, spawnColumn = lineNumber, x+1
+				config.StartX, config.StartY = cell.x, cell.y
+				config.StartDirection = spawnDirection(symbol)
+			case 'F':
+				config.Fruits = append(config.Fruits, cell)
+			case 'P':
+				config.Poisons = append(config.Poisons, cell)
+			default:
+				return levelErrorf(lineNumber, x+1, "unknown symbol %q", symbol)
+			}
+			if x+1 > config.Width {
+				config.Width = x + 1
+			}
+		}
+	}
+	config.Height = len(lines) - first
+	if spawnLine == 0 {
+		return levelErrorf(len(lines), 1, "the level has no snake")
+	}
+	if err := config.Check(); err != nil {
+		return levelErrorf(spawnLine, spawnColumn, "%v", err)
+	}
+	return nil
+}
+
+func spawnDirection(symbol rune) int {
+	switch symbol {
+	case '^':
+		return Up
+	case 'v':
+		return Down
+	case '<':
+		return Left
+	case '>':
+		return Right
+	}
+	return none
+}
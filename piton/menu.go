@@ -0,0 +1,144 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package piton
+
+import (
+	term "github.com/nsf/termbox-go"
+)
+
+const menuResume = 0
+const menuRestart = 1
+const menuSave = 2
+const menuQuit = 3
+
+var menuItems = []string{"Resume", "Restart", "Save", "Quit to menu"}
+
+/*SetSaveHandler sets what the Save entry of the in-game menu of the default game does*/
+func SetSaveHandler(save func(g *Game) error) {
+	defaultGame.SetSaveHandler(save)
+}
+
+/*SetSaveHandler sets what the Save entry of the in-game menu does*/
+func (g *Game) SetSaveHandler(save func(g *Game) error) {
+	g.save = save
+}
+
+/*runMenu shows the in-game menu over the board until the player chooses an entry*/
+func (g *Game) runMenu(keyboard chan term.Event, screen *TermRenderer) int {
+	selected := menuResume
+	message := ""
+	for {
+		g.drawMenu(screen, selected, message)
+		switch keyFromEvent(<-keyboard) {
+		case Up:
+			selected = (selected + len(menuItems) - 1) % len(menuItems)
+		case Down:
+			selected = (selected + 1) % len(menuItems)
+		case Esc:
+			return menuResume
+		case enter:
+			if selected != menuSave {
+				return selected
+			}
+			message = g.saveGame()
+		}
+	}
+}
+
+func (g *Game) saveGame() string {
+	if g.save == nil {
+		return "Saving is not available"
+	}
+	if err := g.save(g); err != nil {
+		return "Not saved: " + err.Error()
+	}
+	return "Game saved"
+}
+
+/*drawMenu draws the board with the menu in the middle*/
+func (g *Game) drawMenu(screen *TermRenderer, selected int, message string) {
+	lines := make([]string, len(menuItems))
+	for i, item := range menuItems {
+		if i == selected {
+			lines[i] = "> " + item
+		} else {
+			lines[i] = "  " + item
+		}
+	}
+	if message != "" {
+		lines = append(lines, "", message)
+	}
+	g.drawOverlay(screen, lines, selected)
+}
+
+/*drawPause draws the board with the pause notice in the middle*/
+func (g *Game) drawPause(screen *TermRenderer) {
+	g.drawOverlay(screen, []string{"PAUSED", "", "Space to resume"}, -1)
+}
+
+/*drawOverlay draws the frozen board and a box with the given lines over it in the middle of the terminal, the highlighted line in reverse*/
+func (g *Game) drawOverlay(screen *TermRenderer, lines []string, highlighted int) {
+	screen.Clear()
+	if !screen.drawGame(g, nil) {
+		term.Flush()
+		return
+	}
+	screenW, screenH := term.Size()
+	width := 0
+	for _, line := range lines {
+		if len([]rune(line)) > width {
+			width = len([]rune(line))
+		}
+	}
+	boxW, boxH := width+4, len(lines)+2
+	left := (screenW - boxW) / 2
+	top := (screenH - boxH) / 2
+	if left < 0 {
+		left = 0
+	}
+	if top < 0 {
+		top = 0
+	}
+	for y := 0; y < boxH; y++ {
+		for x := 0; x < boxW; x++ {
+			ch := ' '
+			switch {
+			case (y == 0 || y == boxH-1) && (x == 0 || x == boxW-1):
+				ch = '+'
+			case y == 0 || y == boxH-1:
+				ch = '-'
+			case x == 0 || x == boxW-1:
+				ch = '|'
+			}
+			term.SetCell(left+x, top+y, ch, term.ColorDefault, term.ColorDefault)
+		}
+	}
+	for i, line := range lines {
+		fg, bg := term.ColorDefault, term.ColorDefault
+		if i == highlighted {
+			fg |= term.AttrReverse
+		}
+		drawText(left+2, top+1+i, line, fg, bg)
+	}
+	term.Flush()
+}
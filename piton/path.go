@@ -0,0 +1,247 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package piton
+
+/*
+PathAgent goes to the nearest fruit along the shortest path found with a breadth first search.
+Before taking the path it plays it on a copy of the board and checks that, once the fruit is eaten,
+the head can still get to the cell of the tail after the tail left it, so it doesn't close itself in.
+A cell of the body counts as a way only from the move the body has left it on, growth included.
+When there is no such path it follows its tail the longest way round,
+and when it can't reach its tail either it goes where it has the most room.
+It can still die when the board is almost full and a new fruit appears on the way to its tail
+*/
+type PathAgent struct{}
+
+func init() {
+	RegisterAgent("path", func() Agent { return PathAgent{} })
+}
+
+/*pathState is a snake on a board, real or imagined*/
+type pathState struct {
+	obs    Observation
+	board  BoardType
+	body   []Coord
+	growth int
+}
+
+/*Move chooses the first step of the safest path*/
+func (PathAgent) Move(obs Observation) int {
+	s := &pathState{obs: obs, board: observedBoard(obs), body: obs.Body(), growth: obs.Growth()}
+	if path := s.search(s.head(), func(c Coord) bool { return IsFruit(s.board[c.y][c.x]) }); len(path) > 0 {
+		after := s.clone()
+		for _, direction := range path {
+			after.advance(direction)
+		}
+		if after.tailReachable() {
+			return path[0]
+		}
+	}
+	if direction := s.followTail(); direction != none {
+		return direction
+	}
+	return s.mostRoom()
+}
+
+func observedBoard(obs Observation) BoardType {
+	board := make(BoardType, obs.Height()+2)
+	for y := range board {
+		board[y] = make([]int, obs.Width()+2)
+		for x := range board[y] {
+			board[y][x] = obs.Cell(x, y)
+		}
+	}
+	return board
+}
+
+func (s *pathState) head() Coord {
+	return s.body[0]
+}
+
+func (s *pathState) tail() Coord {
+	return s.body[len(s.body)-1]
+}
+
+func (s *pathState) clone() *pathState {
+	return &pathState{obs: s.obs, board: copyBoard(s.board), body: append([]Coord{}, s.body...), growth: s.growth}
+}
+
+/*free tells if the head can move on the cell now: it is empty or a fruit. The tail is not, even if it is going away*/
+func (s *pathState) free(c Coord) bool {
+	cell := s.board[c.y][c.x]
+	return cell == Empty || IsFruit(cell)
+}
+
+/*advance moves the imagined snake one cell, eating what is there*/
+func (s *pathState) advance(direction int) {
+	next := s.obs.Neighbor(s.head(), direction)
+	if cell := s.board[next.y][next.x]; IsFruit(cell) {
+		s.growth += s.obs.FruitGrowth(cell)
+	}
+	if s.growth > 0 {
+		s.growth--
+	} else {
+		tail := s.tail()
+		s.board[tail.y][tail.x] = Empty
+		s.body = s.body[:len(s.body)-1]
+	}
+	if len(s.body) > 0 {
+		s.board[s.head().y][s.head().x] = Neck + 1
+	}
+	s.board[next.y][next.x] = Snake
+	s.body = append([]Coord{next}, s.body...)
+}
+
+/*
+search returns the directions of the shortest path from the cell to one that satisfies the goal,
+going only through cells that are passable when the head gets there, nil if there is none
+*/
+func (s *pathState) search(from Coord, goal func(Coord) bool) []int {
+	leaves := s.leaves()
+	came := s.grid()
+	steps := s.grid()
+	came[from.y][from.x] = none
+	queue := []Coord{from}
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		for direction := Right; direction <= Down; direction++ {
+			next := s.obs.Neighbor(c, direction)
+			step := steps[c.y][c.x] + 1
+			if came[next.y][next.x] != 0 || !s.free(next) && (leaves[next.y][next.x] == 0 || step < leaves[next.y][next.x]) {
+				continue
+			}
+			came[next.y][next.x] = direction
+			steps[next.y][next.x] = step
+			if goal(next) {
+				var path []int
+				for at := next; at != from; at = s.obs.Neighbor(at, opposite(came[at.y][at.x])) {
+					path = append(path, came[at.y][at.x])
+				}
+				for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+					path[i], path[j] = path[j], path[i]
+				}
+				return path
+			}
+			queue = append(queue, next)
+		}
+	}
+	return nil
+}
+
+/*
+leaves returns for every cell of the body the first move the head can go there, 0 for the other cells.
+The engine looks at the board before the tail moves, so the tail cell can be taken
+only on the second move after the snake stopped growing, and the segments before it one move later each
+*/
+func (s *pathState) leaves() [][]int {
+	leaves := s.grid()
+	for i := 1; i < len(s.body); i++ {
+		c := s.body[i]
+		leaves[c.y][c.x] = s.growth + len(s.body) - i + 1
+	}
+	return leaves
+}
+
+/*grid returns a board sized grid of zeros*/
+func (s *pathState) grid() [][]int {
+	grid := make([][]int, len(s.board))
+	for y := range grid {
+		grid[y] = make([]int, len(s.board[y]))
+	}
+	return grid
+}
+
+/*tailReachable tells if the head can get to the cell of the tail once the tail left it*/
+func (s *pathState) tailReachable() bool {
+	if len(s.body) < 2 {
+		return true
+	}
+	tail := s.tail()
+	return s.search(s.head(), func(c Coord) bool { return c == tail }) != nil
+}
+
+/*followTail returns the safe direction that keeps the tail reachable by the longest way, none if there is none*/
+func (s *pathState) followTail() int {
+	best, bestLength := none, -1
+	for direction := Right; direction <= Down; direction++ {
+		next := s.obs.Neighbor(s.head(), direction)
+		if !s.free(next) {
+			continue
+		}
+		after := s.clone()
+		after.advance(direction)
+		if len(after.body) < 2 {
+			return direction
+		}
+		tail := after.tail()
+		path := after.search(after.head(), func(c Coord) bool { return c == tail })
+		if path != nil && len(path) > bestLength {
+			best, bestLength = direction, len(path)
+		}
+	}
+	return best
+}
+
+/*mostRoom returns the direction that leaves the most cells reachable, the current one when every direction is deadly*/
+func (s *pathState) mostRoom() int {
+	best, bestRoom := none, -1
+	for direction := Right; direction <= Down; direction++ {
+		if !s.free(s.obs.Neighbor(s.head(), direction)) {
+			continue
+		}
+		after := s.clone()
+		after.advance(direction)
+		if room := after.room(); room > bestRoom {
+			best, bestRoom = direction, room
+		}
+	}
+	if best == none {
+		if direction := s.obs.Direction(); direction >= Right && direction <= Down {
+			return direction
+		}
+		return Up
+	}
+	return best
+}
+
+/*room counts the free cells the head can reach*/
+func (s *pathState) room() int {
+	seen := s.grid()
+	seen[s.head().y][s.head().x] = 1
+	queue := []Coord{s.head()}
+	room := 0
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		for direction := Right; direction <= Down; direction++ {
+			next := s.obs.Neighbor(c, direction)
+			if seen[next.y][next.x] == 0 && s.free(next) {
+				seen[next.y][next.x] = 1
+				room++
+				queue = append(queue, next)
+			}
+		}
+	}
+	return room
+}
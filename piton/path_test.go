@@ -0,0 +1,50 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package piton
+
+import "testing"
+
+/*playSeeded lets the agent play a game with the given seed until it is over*/
+func playSeeded(t *testing.T, agent Agent, config BoardConfig, seed int64) *Game {
+	g := CreateGameWithRenderer(nil)
+	if err := g.NewGame(&config, &GameStatus{Seed: seed}); err != nil {
+		t.Fatal(err)
+	}
+	for !g.Step(agent.Move(g.Observe())).GameOver {
+	}
+	return g
+}
+
+func TestPathAgentFillsNinetyPercentOfTheBoard(t *testing.T) {
+	for _, size := range []Coord{{20, 10}, {10, 10}} {
+		for seed := int64(1); seed <= 4; seed++ {
+			config := BoardConfig{Width: size.x, Height: size.y, MaxMoves: 20000}
+			g := playSeeded(t, PathAgent{}, config, seed)
+			area := size.x * size.y
+			if !g.Won() && g.Length()*10 < area*9 {
+				t.Errorf("%dx%d seed %d: game over with death cause %d at length %d of %d",
+					size.x, size.y, seed, g.DeathCause(), g.Length(), area)
+			}
+		}
+	}
+}
@@ -27,7 +27,6 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
-	"os/exec"
 	"time"
 
 	term "github.com/nsf/termbox-go"
@@ -39,9 +38,25 @@ type Coord struct {
 	y int
 }
 
-/*GameStatus retains the status for all the things that matters in a game*/
+/*X returns the x coord*/
+func (c Coord) X() int {
+	return c.x
+}
+
+/*Y returns the y coord*/
+func (c Coord) Y() int {
+	return c.y
+}
+
+/*
+GameStatus retains the status for all the things that matters in a game.
+Seed feeds the random source of the game, a game with the same seed and the same moves
+is always the same game. Seed left to zero is taken from the clock
+*/
 type GameStatus struct {
-	fruits []Coord
+	Seed    int64
+	fruits  []scheduledFruit
+	poisons []scheduledPoison
 }
 
 /*GameSequence is the type of the array that describes a game*/
@@ -87,6 +102,7 @@ const Q4 = 8
 
 const space = 9
 const enter = 10
+const backspace = 11
 
 /*Wall value*/
 const Wall = -1
@@ -106,34 +122,160 @@ const Fruit = -2
 /*Poison value*/
 const Poison = -3
 
-/*MaxGameSequenceLength is the maximum number of moves the player can do during the game*/
+/*GoldenFruit value*/
+const GoldenFruit = -4
+
+/*TimedFruit value*/
+const TimedFruit = -5
+
+/*MaxGameSequenceLength is the least number of moves an agent can make when the board has no MaxMoves*/
 const MaxGameSequenceLength = 10000
 
-/*MaxGameScore is the maximum possible score before the game ends*/
+/*MaxGameScore is the default score that wins the game*/
 const MaxGameScore = 1000
 
-/*CurrentBoard holds the current board */
+/*NoTargetScore as the TargetScore of a board makes the game go on until there is no room left for a fruit*/
+const NoTargetScore = -1
+
+/*CurrentBoard holds the board of the default game*/
 var CurrentBoard BoardType
-var startingBoard BoardType
-var snakeXHead, snakeYHead, snakeXTail, snakeYTail int
-var fruitX, fruitY int
-var direction int
-var currentFruitIndex int
-var score int
+
+/*syncCurrentBoard points CurrentBoard to the board of the game after it got a new one, if it is the default game*/
+func (g *Game) syncCurrentBoard() {
+	if g == defaultGame {
+		CurrentBoard = g.board
+	}
+}
+
+/*
+Game holds everything about a single game: board, snake, fruits, score and random source.
+Many games can be played side by side, each one on its own Game
+*/
+type Game struct {
+	config            BoardConfig
+	board             BoardType
+	startingBoard     BoardType
+	body              snakeBody
+	fruits            []fruit
+	growth            int
+	direction         int
+	currentFruitIndex int
+	moves             int
+	poisonCount       int
+	difficulty        Difficulty
+	turns             []int
+	status            *GameStatus
+	death             int
+	won               bool
+	score             int
+	seed              int64
+	rng               *rand.Rand
+	moveRng           *rand.Rand
+	rngSource         *countingSource
+	moveSource        *countingSource
+	sequence          GameSequence
+	save              func(g *Game) error
+	theme             *Theme
+	minimap           bool
+	agent             Agent
+	renderer          Renderer
+	scoreBook         *ScoreBook
+	rewindTicks       int
+	practice          bool
+}
+
+var defaultGame = CreateGame()
+
+/*CreateGame creates a new game with its own random source, shown as text on the standard output*/
+func CreateGame() *Game {
+	return CreateGameWithRenderer(NewTextRenderer(os.Stdout))
+}
+
+/*CreateGameWithRenderer creates a new game with its own random source, shown by the renderer*/
+func CreateGameWithRenderer(r Renderer) *Game {
+	g := &Game{config: DefaultBoardConfig(), difficulty: difficulties[1]}
+	g.SetRenderer(r)
+	g.SetTheme(&themes[0])
+	g.SetAgent(nil)
+	g.setSeed(clockSeed())
+	g.initBoard()
+	return g
+}
+
+func clockSeed() int64 {
+	return time.Now().UTC().UnixNano()
+}
+
+/*
+setSeed restarts the random sources from the seed. The moves of the computer have their own source,
+so the fruits and the poisons are the same whether the moves are chosen at random or replayed
+*/
+func (g *Game) setSeed(seed int64) {
+	g.seed = seed
+	g.rngSource = newCountingSource(seed)
+	g.moveSource = newCountingSource(seed + 1)
+	g.rng = rand.New(g.rngSource)
+	g.moveRng = rand.New(g.moveSource)
+}
+
+/*Seed returns the seed of the default game*/
+func Seed() int64 {
+	return defaultGame.Seed()
+}
+
+/*Seed returns the seed of the random source of the game, to play it again*/
+func (g *Game) Seed() int64 {
+	return g.seed
+}
+
+/*Config returns the board config of the game, with the defaults filled in*/
+func (g *Game) Config() BoardConfig {
+	return g.config
+}
+
+/*Board returns the board of the game*/
+func (g *Game) Board() BoardType {
+	return g.board
+}
+
+/*Score returns the current score of the default game*/
+func Score() int {
+	return defaultGame.Score()
+}
+
+/*Score returns the current score of the game*/
+func (g *Game) Score() int {
+	return g.score
+}
 
 /*GetSnakeDirection returns the direction the snake is moving to*/
 func GetSnakeDirection() int {
-	return direction
+	return defaultGame.GetSnakeDirection()
+}
+
+/*GetSnakeDirection returns the direction the snake is moving to*/
+func (g *Game) GetSnakeDirection() int {
+	return g.direction
 }
 
 /*GetSnakeX returns the x coord of the head of the snake*/
 func GetSnakeX() int {
-	return snakeXHead
+	return defaultGame.GetSnakeX()
+}
+
+/*GetSnakeX returns the x coord of the head of the snake*/
+func (g *Game) GetSnakeX() int {
+	return g.body.headCoord().x
 }
 
 /*GetSnakeY returns the y coord of the head of the snake*/
 func GetSnakeY() int {
-	return snakeYHead
+	return defaultGame.GetSnakeY()
+}
+
+/*GetSnakeY returns the y coord of the head of the snake*/
+func (g *Game) GetSnakeY() int {
+	return g.body.headCoord().y
 }
 
 /*IsDanger returns true if the cell is dangerous*/
@@ -146,7 +288,7 @@ func IsDanger(cell int) bool {
 
 /*IsFruit returns true if the cell is a fruit*/
 func IsFruit(cell int) bool {
-	if cell == Fruit {
+	if cell == Fruit || cell == GoldenFruit || cell == TimedFruit {
 		return true
 	}
 	return false
@@ -154,33 +296,54 @@ func IsFruit(cell int) bool {
 
 /*SnakeHeadNeighbor returns the cell next to the snake's in the given direction*/
 func SnakeHeadNeighbor(where int) int {
-	var cell int
+	return defaultGame.SnakeHeadNeighbor(where)
+}
+
+/*SnakeHeadNeighbor returns the cell next to the snake's in the given direction*/
+func (g *Game) SnakeHeadNeighbor(where int) int {
+	var next Coord
+	head := g.body.headCoord()
 	switch where {
 	case Right:
-		cell = CurrentBoard[snakeYHead][snakeXHead+1]
+		next = g.neighbor(head, Right)
 	case Left:
-		cell = CurrentBoard[snakeYHead][snakeXHead-1]
+		next = g.neighbor(head, Left)
 	case Up:
-		cell = CurrentBoard[snakeYHead+1][snakeXHead]
+		next = g.neighbor(head, Up)
 	case Down:
-		cell = CurrentBoard[snakeYHead-1][snakeXHead]
+		next = g.neighbor(head, Down)
+	default:
+		return Empty
 	}
-	return cell
+	return g.board[next.y][next.x]
 }
 
 /*FruitLocation returns the quadrant of the fruit relative to the pivot location*/
 func FruitLocation(pivotX int, pivotY int) int {
+	return defaultGame.FruitLocation(pivotX, pivotY)
+}
+
+/*
+FruitLocation returns where the nearest fruit is seen from the pivot location: Right, Left, Up or Down
+when it is on the same row or column, the quadrant Q1 to Q4 otherwise, 0 when there are no fruits
+*/
+func (g *Game) FruitLocation(pivotX int, pivotY int) int {
 	var where int
+	fruitX, fruitY := g.NearestFruit(pivotX, pivotY)
+	if fruitX == -1 {
+		return where
+	}
+	fruitX, fruitY = g.nearestImage(fruitX, fruitY, pivotX, pivotY)
 	if fruitY == pivotY && fruitX > pivotX {
 		where = Right
 	}
 	if fruitY == pivotY && fruitX < pivotX {
 		where = Left
 	}
-	if fruitX == pivotY && fruitY < pivotY {
+	if fruitX == pivotX && fruitY < pivotY {
 		where = Up
 	}
-	if fruitX == pivotY && fruitY > pivotY {
+	if fruitX == pivotX && fruitY > pivotY {
 		where = Down
 	}
 
@@ -201,52 +364,72 @@ func FruitLocation(pivotX int, pivotY int) int {
 
 /*Init all in Severus*/
 func Init() {
-	rand.Seed(time.Now().UTC().UnixNano())
-	initBoard()
+	defaultGame.initBoard()
+	defaultGame.syncCurrentBoard()
 }
 
-/*NewGame init the game*/
-func NewGame(game *GameStatus) {
-	score = 0
-	initBoard()
-	initSnake()
-	initFruit(game)
+/*NewGame init the default game*/
+func NewGame(config *BoardConfig, game *GameStatus) error {
+	return defaultGame.NewGame(config, game)
 }
 
-func initBoard() {
-	initBoard := BoardType{
-		{-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1},
-		{-1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, -1},
-		{-1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, -1},
-		{-1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, -1},
-		{-1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, -1},
-		{-1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, -1},
-		{-1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, -1},
-		{-1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, -1},
-		{-1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, -1},
-		{-1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, -1},
-		{-1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, -1},
-		{-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1},
+/*
+NewGame init the game on the board described by config, the default board when config is nil.
+It returns the error of Check when the snake doesn't fit on the board, and then the game is left as it was
+*/
+func (g *Game) NewGame(config *BoardConfig, game *GameStatus) error {
+	boardConfig := DefaultBoardConfig()
+	if config != nil {
+		boardConfig = config.withDefaults()
+	}
+	if err := boardConfig.Check(); err != nil {
+		return err
+	}
+	g.config = boardConfig
+	if game != nil && game.Seed != 0 {
+		g.setSeed(game.Seed)
+	} else {
+		g.setSeed(clockSeed())
 	}
-	startingBoard = initBoard
-	CurrentBoard = initBoard
+	g.status = game
+	g.death = DeathNone
+	g.won = false
+	g.practice = false
+	g.score = 0
+	g.moves = 0
+	g.turns = g.turns[:0]
+	g.sequence = nil
+	g.initBoard()
+	g.initSnake()
+	g.initFruit(game)
+	g.syncCurrentBoard()
+	return nil
 }
 
-func initFruit(game *GameStatus) {
-	currentFruitIndex = 0
-	respawnFruit(game)
+func (g *Game) initBoard() {
+	g.board = g.config.makeBoard()
+	g.startingBoard = g.board
 }
 
-func initSnake() {
-	snakeXHead = startingX
-	snakeYHead = startingY
-	snakeXTail = startingX
-	snakeYTail = startingY + 2
+func (g *Game) initSnake() {
+	g.body = newSnakeBody(g.config.Width * g.config.Height)
+	behind := opposite(g.config.startingHeading())
+	cell := Coord{g.config.StartX, g.config.StartY}
+	for i := 0; i < g.config.SnakeLength; i++ {
+		g.board[cell.y][cell.x] = Neck + 1
+		cell = nextCoord(cell, behind)
+	}
+	for i := 0; i < g.config.SnakeLength; i++ {
+		cell = nextCoord(cell, g.config.startingHeading())
+		g.body.pushHead(cell)
+	}
+	if g.body.len() > 1 {
+		neck := g.body.at(1)
+		g.board[neck.y][neck.x] = Neck
+	}
+	g.board[g.config.StartY][g.config.StartX] = Snake
 
-	direction = none
-	CurrentBoard[snakeYHead][snakeXHead] = Snake
-	CurrentBoard[snakeYTail-1][snakeXTail] = Snake + 1
-	CurrentBoard[snakeYTail][snakeXTail] = Snake + 2
+	g.direction = g.config.StartDirection
 }
 
 /*Close releases everything*/
@@ -259,6 +442,9 @@ func InitTerm() {
 	if err != nil {
 		panic(err)
 	}
+	if DetectTerminal().Colors >= 256 {
+		term.SetOutputMode(term.Output256)
+	}
 }
 
 /*CloseTerm closes the terminal*/
@@ -266,25 +452,39 @@ func CloseTerm() {
 	term.Close()
 }
 
-func getNewFruitCoords(board *BoardType) (int, int) {
-	fruitX = -1
-	fruitY = -1
-watchDog:
-	for count := 0; count < 10; count++ {
-		x := rand.Intn(playableBoardW) + 1
-		y := rand.Intn(playableBoardH) + 1
-		if isEmpty(board, x, y) {
-			fruitX = x
-			fruitY = y
-			break watchDog
+func (g *Game) getNewFruitCoords(board *BoardType) (int, int) {
+	return g.getRandomEmptyCell(board)
+}
+
+/*getRandomEmptyCell picks one of the empty cells of the board, all with the same chance, or -1, -1 if there are none*/
+func (g *Game) getRandomEmptyCell(board *BoardType) (int, int) {
+	empty := 0
+	for y := 1; y <= g.config.Height; y++ {
+		for x := 1; x <= g.config.Width; x++ {
+			if isEmpty(board, x, y) {
+				empty++
+			}
 		}
 	}
-
-	return fruitX, fruitY
+	if empty == 0 {
+		return -1, -1
+	}
+	chosen := g.rng.Intn(empty)
+	for y := 1; y <= g.config.Height; y++ {
+		for x := 1; x <= g.config.Width; x++ {
+			if isEmpty(board, x, y) {
+				if chosen == 0 {
+					return x, y
+				}
+				chosen--
+			}
+		}
+	}
+	return -1, -1
 }
 
-func isInsideSnakeBody(x int, y int) bool {
-	if CurrentBoard[y][x] >= Snake {
+func (g *Game) isInsideSnakeBody(x int, y int) bool {
+	if g.board[y][x] >= Snake {
 		return true
 	}
 	return false
@@ -297,40 +497,19 @@ func isEmpty(board *BoardType, x int, y int) bool {
 	return false
 }
 
-/*ClearConsole clears the console*/
+/*ClearConsole clears the console and moves the cursor to the top left corner, with ANSI escape codes*/
 func ClearConsole() {
-	cmd := exec.Command("cmd", "/c", "cls")
-	cmd.Stdout = os.Stdout
-	cmd.Run()
+	fmt.Print("\033[H\033[2J")
 }
 
 /*OutputBoard prints the board on screen*/
 func OutputBoard(board BoardType) {
-	//fmt.Println("board h = ", len(board))
-	//fmt.Println("board w = ", len(board[0]))
-	for _, row := range CurrentBoard {
-		for _, checker := range row {
-			if checker != Wall {
-				if checker == Empty {
-					fmt.Print(".")
-				}
-				if checker == Snake { // Snake's head
-					fmt.Print("@")
-				} else {
-					if checker > Snake {
-						fmt.Print("O")
-					}
-				}
-				if checker == Fruit {
-					fmt.Print("F")
-				}
-				if checker == Poison {
-					fmt.Print("P")
-				}
-			}
-		}
-		fmt.Println()
-	}
+	writeBoard(os.Stdout, board)
+}
+
+/*OutputBoard prints the board of the game on screen*/
+func (g *Game) OutputBoard() {
+	writeBoard(os.Stdout, g.board)
 }
 
 func bufferedReadKey() byte {
@@ -359,549 +538,337 @@ func KeyPressed(keyboard chan term.Event, waitFlag bool) rune {
 	key = none
 	var waitTime int
 	if waitFlag {
-		waitTime = 250
+		waitTime = defaultTick
 	} else {
 		waitTime = 0
 	}
 	select {
 	case ev := <-keyboard:
-		if ev.Type == term.EventKey {
-			switch ev.Key {
-			case term.KeyEsc:
-				term.Sync()
-				fmt.Println("Esc pressed")
-				key = Esc
-			case term.KeyArrowUp:
-				key = Up
-				term.Sync()
-				fmt.Println("Arrow Up pressed")
-			case term.KeyArrowDown:
-				key = Down
-				term.Sync()
-				fmt.Println("Arrow Down pressed")
-			case term.KeyArrowLeft:
-				key = Left
-				term.Sync()
-				fmt.Println("Arrow Left pressed")
-			case term.KeyArrowRight:
-				key = Right
-				term.Sync()
-				fmt.Println("Arrow Right pressed")
-			case term.KeySpace:
-				key = space
-				term.Sync()
-				fmt.Println("Space pressed")
-			case term.KeyEnter:
-				key = enter
-				term.Sync()
-				fmt.Println("Enter pressed")
-			default:
-				key = none
-				term.Sync()
-				fmt.Println("ASCII : ", ev.Ch)
-			}
-		}
+		key = keyFromEvent(ev)
 	case <-time.After(time.Duration(waitTime) * time.Millisecond):
 	}
 	return key
 }
 
-func snakeSetRight() {
-	direction = Right
-}
-
-func snakeSetLeft() {
-	direction = Left
-}
-
-func snakeSetUp() {
-	direction = Up
-}
-
-func snakeSetDown() {
-	direction = Down
-}
-
-func getNextFruit(game *GameStatus) (int, int) {
-	x := game.fruits[currentFruitIndex].x
-	y := game.fruits[currentFruitIndex].y
-	currentFruitIndex++
-	return x, y
-}
-
-func respawnFruit(game *GameStatus) {
-	if game == nil {
-		fruitX, fruitY = getNewFruitCoords(&CurrentBoard)
-		if fruitX != -1 && fruitY != -1 {
-			CurrentBoard[fruitY][fruitX] = Fruit
+/*keyFromEvent decodes a keyboard event*/
+func keyFromEvent(ev term.Event) rune {
+	var key rune
+	key = none
+	if ev.Type == term.EventKey {
+		switch ev.Key {
+		case term.KeyEsc:
+			key = Esc
+		case term.KeyArrowUp:
+			key = Up
+		case term.KeyArrowDown:
+			key = Down
+		case term.KeyArrowLeft:
+			key = Left
+		case term.KeyArrowRight:
+			key = Right
+		case term.KeySpace:
+			key = space
+		case term.KeyBackspace, term.KeyBackspace2:
+			key = backspace
+		case term.KeyEnter:
+			key = enter
+		default:
+			key = none
 		}
-	} else {
-		fruitX, fruitY = getNextFruit(game)
 	}
-	CurrentBoard[fruitY][fruitX] = Fruit
+	return key
+}
 
-	score++
+func (g *Game) snakeProceedGivenMatch(status *GameStatus) bool {
+	return g.snakeProceedImpl(status)
 }
 
-func snakeProceedGivenMatch(status *GameStatus) bool {
-	return snakeProceedImpl(status)
+func (g *Game) snakeProceed() bool {
+	return g.snakeProceedImpl(nil)
 }
 
-func snakeProceed() bool {
-	return snakeProceedImpl(nil)
+func (g *Game) snakeProceedImpl(status *GameStatus) bool {
+	return g.proceed(status).GameOver
 }
 
-func snakeProceedImpl(status *GameStatus) bool {
-	var gameOver bool = false
+/*nextCoord returns the cell next to c in the given direction*/
+func nextCoord(c Coord, direction int) Coord {
 	switch direction {
 	case Right:
-		if canGo, what := snakeCanGoRight(); canGo {
-			if what == Fruit {
-				snakeGrowRight()
-				respawnFruit(status)
-			} else {
-				snakeMoveRight()
-			}
-		} else {
-			if what == Neck {
-				// do nothing
-			} else {
-				snakeDies()
-				gameOver = true
-			}
-		}
+		c.x++
 	case Left:
-		if canGo, what := snakeCanGoLeft(); canGo {
-			if what == Fruit {
-				snakeGrowLeft()
-				respawnFruit(status)
-			} else {
-				snakeMoveLeft()
-			}
-		} else {
-			if what == Neck {
-				// do nothing
-			} else {
-				snakeDies()
-				gameOver = true
-			}
-		}
+		c.x--
 	case Up:
-		if canGo, what := snakeCanGoUp(); canGo {
-			if what == Fruit {
-				snakeGrowUp()
-				respawnFruit(status)
-			} else {
-				snakeMoveUp()
-			}
-		} else {
-			if what == Neck {
-				// do nothing
-			} else {
-				snakeDies()
-				gameOver = true
-			}
-		}
+		c.y--
 	case Down:
-		if canGo, what := snakeCanGoDown(); canGo {
-			if what == Fruit {
-				snakeGrowDown()
-				respawnFruit(status)
-			} else {
-				snakeMoveDown()
-			}
-		} else {
-			if what == Neck {
-				// do nothing
-			} else {
-				snakeDies()
-				gameOver = true
-			}
-		}
+		c.y++
 	}
-
-	return gameOver
+	return c
 }
 
-func snakeCanGoRight() (bool, int) {
-	if CurrentBoard[snakeYHead][snakeXHead+1] == Empty {
-		return true, Empty
-	}
-	if CurrentBoard[snakeYHead][snakeXHead+1] >= Snake {
-		if CurrentBoard[snakeYHead][snakeXHead+1] == Neck {
-			return false, Neck
-		}
-		return false, Snake
-	}
-	if CurrentBoard[snakeYHead][snakeXHead+1] == Fruit {
-		return true, Fruit
-	}
-	if CurrentBoard[snakeYHead][snakeXHead+1] == Poison {
-		return true, Poison
+func (g *Game) snakeCanGoDirection(desiredDirection int) (bool, int) {
+	if desiredDirection < Right || desiredDirection > Down {
+		return false, -1
 	}
-	return false, Wall
-}
-
-func snakeCanGoLeft() (bool, int) {
-	if CurrentBoard[snakeYHead][snakeXHead-1] == Empty {
-		return true, Empty
-	}
-	if CurrentBoard[snakeYHead][snakeXHead-1] >= Snake {
-		if CurrentBoard[snakeYHead][snakeXHead-1] == Neck {
-			return false, Neck
-		}
-		return false, Snake
-	}
-	if CurrentBoard[snakeYHead][snakeXHead-1] == Fruit {
-		return true, Fruit
-	}
-	if CurrentBoard[snakeYHead][snakeXHead-1] == Poison {
-		return true, Poison
-	}
-	return false, Wall
-
-}
-
-func snakeCanGoUp() (bool, int) {
-	if CurrentBoard[snakeYHead-1][snakeXHead] == Empty {
+	next := g.neighbor(g.body.headCoord(), desiredDirection)
+	cell := g.board[next.y][next.x]
+	if cell == Empty {
 		return true, Empty
 	}
-	if CurrentBoard[snakeYHead-1][snakeXHead] >= Snake {
-		if CurrentBoard[snakeYHead-1][snakeXHead] == Neck {
+	if cell >= Snake {
+		if cell == Neck {
 			return false, Neck
 		}
 		return false, Snake
 	}
-	if CurrentBoard[snakeYHead-1][snakeXHead] == Fruit {
+	if IsFruit(cell) {
 		return true, Fruit
 	}
-	if CurrentBoard[snakeYHead-1][snakeXHead] == Poison {
+	if cell == Poison {
 		return true, Poison
 	}
 	return false, Wall
 }
 
-func snakeCanGoDown() (bool, int) {
-	if CurrentBoard[snakeYHead+1][snakeXHead] == Empty {
-		return true, Empty
-	}
-	if CurrentBoard[snakeYHead+1][snakeXHead] >= Snake {
-		if CurrentBoard[snakeYHead+1][snakeXHead] == Neck {
-			return false, Neck
-		}
-		return false, Snake
-	}
-	if CurrentBoard[snakeYHead+1][snakeXHead] == Fruit {
-		return true, Fruit
-	}
-	if CurrentBoard[snakeYHead+1][snakeXHead] == Poison {
-		return true, Poison
-	}
-	return false, Wall
+func (g *Game) snakeDies(cause int) {
+	g.death = cause
 }
 
-func snakeCanGoDirection(desiredDirection int) (bool, int) {
-	if desiredDirection == Right {
-		return snakeCanGoRight()
-	}
-	if desiredDirection == Left {
-		return snakeCanGoLeft()
+/*
+advanceSnake moves the head one cell in the current direction, leaving the tail in place when the snake grows.
+Only the new head, the old head, the old neck and the old tail change on the board
+*/
+func (g *Game) advanceSnake(grow bool) {
+	head := g.neighbor(g.body.headCoord(), g.direction)
+	if !grow {
+		tail := g.body.popTail()
+		g.board[tail.y][tail.x] = Empty
 	}
-	if desiredDirection == Up {
-		return snakeCanGoUp()
+	if g.body.len() > 1 {
+		neck := g.body.at(1)
+		g.board[neck.y][neck.x] = Neck + 1
 	}
-	if desiredDirection == Down {
-		return snakeCanGoDown()
+	if g.body.len() > 0 {
+		oldHead := g.body.headCoord()
+		g.board[oldHead.y][oldHead.x] = Neck
 	}
-	return false, -1
+	g.body.pushHead(head)
+	g.board[head.y][head.x] = Snake
 }
 
-func snakeDies() {
-
+/*HumanPlay lets you play the default game*/
+func HumanPlay() int {
+	return defaultGame.HumanPlay()
 }
 
-func moveSnake(x int, y int, snake int) {
-	if CurrentBoard[y][x-1] == snake {
-		if y == snakeYTail && x-1 == snakeXTail {
-			CurrentBoard[y][x-1] = Empty
-			snakeXTail = x
-			return
-		}
-		CurrentBoard[y][x-1] = snake + 1
-		moveSnake(x-1, y, snake+1)
-		return
-	}
-	if CurrentBoard[y][x+1] == snake {
-		if y == snakeYTail && x+1 == snakeXTail {
-			CurrentBoard[y][x+1] = Empty
-			snakeXTail = x
-			return
-		}
-		CurrentBoard[y][x+1] = snake + 1
-		moveSnake(x+1, y, snake+1)
-		return
-	}
-	if CurrentBoard[y-1][x] == snake {
-		if y-1 == snakeYTail && x == snakeXTail {
-			CurrentBoard[y-1][x] = Empty
-			snakeYTail = y
-			return
+/*
+HumanPlay lets you play the game.
+The snake moves at the pace of the difficulty, whatever the keys pressed in between.
+A panel next to the board shows how the game is going and a summary is shown when it ends
+*/
+func (g *Game) HumanPlay() int {
+	InitTerm()
+	defer CloseTerm()
+	keyboard := GenKeyboardEventQueue()
+	tick := g.TickDuration()
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	var gameOver bool = false
+	paused, tooSmall := false, false
+	history := newRewindRing(g.rewindTicks)
+	clock := &stopwatch{}
+	screen := NewTermRenderer()
+	screen.theme = g.theme
+	draw := func() {
+		tooSmall = !screen.drawGame(g, g.hudLines(clock, g.bestScore()))
+		screen.Flush()
+	}
+	// the ticker and the clock run only while the game can be played
+	run := func() {
+		if paused || tooSmall {
+			ticker.Stop()
+			clock.stop()
+		} else {
+			tick = g.TickDuration()
+			ticker.Reset(tick)
+			clock.start()
 		}
-		CurrentBoard[y-1][x] = snake + 1
-		moveSnake(x, y-1, snake+1)
-		return
 	}
-	if CurrentBoard[y+1][x] == snake {
-		if y+1 == snakeYTail && x == snakeXTail {
-			CurrentBoard[y+1][x] = Empty
-			snakeYTail = y
-			return
+	draw()
+	run()
+mainLoop:
+	for !gameOver {
+		select {
+		case ev := <-keyboard:
+			if ev.Type == term.EventResize {
+				wasSmall := tooSmall
+				screen.Clear()
+				draw()
+				if paused && !tooSmall {
+					g.drawPause(screen)
+				}
+				if tooSmall != wasSmall {
+					run()
+				}
+				continue
+			}
+			switch key := keyFromEvent(ev); key {
+			case Right, Left, Up, Down:
+				if !paused && !tooSmall {
+					g.QueueTurn(int(key))
+				}
+			case space:
+				if tooSmall {
+					break
+				}
+				paused = !paused
+				if paused {
+					g.drawPause(screen)
+				} else {
+					screen.Clear()
+					draw()
+				}
+				run()
+			case backspace:
+				if !paused && !tooSmall && g.rewind(history) {
+					draw()
+					run()
+				}
+			case Esc:
+				ticker.Stop()
+				clock.stop()
+				switch g.runMenu(keyboard, screen) {
+				case menuRestart:
+					g.recordScore()
+					g.NewGame(&g.config, g.status)
+					history.clear()
+					clock = &stopwatch{}
+				case menuQuit:
+					g.recordScore()
+					break mainLoop
+				}
+				paused = false
+				screen.Clear()
+				draw()
+				run()
+			}
+		case <-ticker.C:
+			if g.rewindTicks > 0 {
+				history.push(g.snapshot())
+			}
+			g.nextTurn()
+			gameOver = g.snakeProceedGivenMatch(g.status)
+			draw()
+			if gameOver {
+				ticker.Stop()
+				clock.stop()
+				newBest := g.recordScore()
+				if g.showSummary(keyboard, screen, clock, g.bestScore(), newBest, history.count > 0) && g.rewind(history) {
+					gameOver = false
+					screen.Clear()
+					draw()
+					run()
+				}
+			} else if next := g.TickDuration(); next != tick || tooSmall {
+				run()
+			}
 		}
-		CurrentBoard[y+1][x] = snake + 1
-		moveSnake(x, y+1, snake+1)
-		return
 	}
+	return g.score
 }
 
-func growSnake(x int, y int, snake int) {
-	if CurrentBoard[y][x-1] == snake {
-		if y == snakeYTail && x-1 == snakeXTail {
-			CurrentBoard[y][x-1] = snake + 1
-			//snakeXTail = x
-			return
+/*generateFruits places the scheduled fruits on the empty cells of the starting board, all with the same chance*/
+func (g *Game) generateFruits(game *GameStatus) {
+	board := g.config.makeBoard()
+	var empty []Coord
+	for y := 1; y <= g.config.Height; y++ {
+		for x := 1; x <= g.config.Width; x++ {
+			if isEmpty(&board, x, y) {
+				empty = append(empty, Coord{x, y})
+			}
 		}
-		CurrentBoard[y][x-1] = snake + 1
-		growSnake(x-1, y, snake+1)
-		return
 	}
-	if CurrentBoard[y][x+1] == snake {
-		if y == snakeYTail && x+1 == snakeXTail {
-			CurrentBoard[y][x+1] = snake + 1
-			//snakeXTail = x
-			return
-		}
-		CurrentBoard[y][x+1] = snake + 1
-		growSnake(x+1, y, snake+1)
+	if len(empty) == 0 {
+		game.fruits = nil
 		return
 	}
-	if CurrentBoard[y-1][x] == snake {
-		if y-1 == snakeYTail && x == snakeXTail {
-			CurrentBoard[y-1][x] = snake + 1
-			//snakeYTail = y
-			return
-		}
-		CurrentBoard[y-1][x] = snake + 1
-		growSnake(x, y-1, snake+1)
-		return
+	for i := range game.fruits {
+		game.fruits[i].at = empty[g.rng.Intn(len(empty))]
+		game.fruits[i].kind = g.randomFruitKind()
 	}
-	if CurrentBoard[y+1][x] == snake {
-		if y+1 == snakeYTail && x == snakeXTail {
-			CurrentBoard[y+1][x] = snake + 1
-			//snakeYTail = y
-			return
-		}
-		CurrentBoard[y+1][x] = snake + 1
-		growSnake(x, y+1, snake+1)
-		return
-	}
-}
-
-func snakeMoveRight() {
-	snakeXHead = snakeXHead + 1
-	CurrentBoard[snakeYHead][snakeXHead] = Snake
-	moveSnake(snakeXHead, snakeYHead, Snake)
-}
-
-func snakeMoveLeft() {
-	snakeXHead = snakeXHead - 1
-	CurrentBoard[snakeYHead][snakeXHead] = Snake
-	moveSnake(snakeXHead, snakeYHead, Snake)
 }
 
-func snakeMoveUp() {
-	snakeYHead = snakeYHead - 1
-	CurrentBoard[snakeYHead][snakeXHead] = Snake
-	moveSnake(snakeXHead, snakeYHead, Snake)
-}
-
-func snakeMoveDown() {
-	snakeYHead = snakeYHead + 1
-	CurrentBoard[snakeYHead][snakeXHead] = Snake
-	moveSnake(snakeXHead, snakeYHead, Snake)
-}
-
-func snakeGrowRight() {
-	snakeXHead = snakeXHead + 1
-	CurrentBoard[snakeYHead][snakeXHead] = Snake
-	growSnake(snakeXHead, snakeYHead, Snake)
-}
-
-func snakeGrowLeft() {
-	snakeXHead = snakeXHead - 1
-	CurrentBoard[snakeYHead][snakeXHead] = Snake
-	growSnake(snakeXHead, snakeYHead, Snake)
+/*GenerateGameParams generates a configuration fot the default game*/
+func GenerateGameParams() GameStatus {
+	return defaultGame.GenerateGameParams()
 }
 
-func snakeGrowUp() {
-	snakeYHead = snakeYHead - 1
-	CurrentBoard[snakeYHead][snakeXHead] = Snake
-	growSnake(snakeXHead, snakeYHead, Snake)
+/*GenerateGameParams generates a configuration fot the game, fruits come from a new seed*/
+func (g *Game) GenerateGameParams() GameStatus {
+	return g.GenerateGameParamsFromSeed(clockSeed())
 }
 
-func snakeGrowDown() {
-	snakeYHead = snakeYHead + 1
-	CurrentBoard[snakeYHead][snakeXHead] = Snake
-	growSnake(snakeXHead, snakeYHead, Snake)
+/*
+GenerateGameParamsFromSeed generates a configuration fot the game, the same seed gives the same fruits.
+It schedules one fruit for every point of the target score, but no more than the cells of the board,
+as the snake can't eat more than that; when the schedule is over the fruits come from the seed of the game
+*/
+func (g *Game) GenerateGameParamsFromSeed(seed int64) GameStatus {
+	var status GameStatus
+	status.Seed = seed
+	count := g.config.Width * g.config.Height
+	if g.config.TargetScore > 0 && g.config.TargetScore < count {
+		count = g.config.TargetScore
+	}
+	status.fruits = make([]scheduledFruit, count)
+	generator := &Game{config: g.config}
+	generator.setSeed(seed)
+	generator.generateFruits(&status)
+	return status
 }
 
-/*HumanPlay lets you play the game*/
-func HumanPlay() int {
-	InitTerm()
-	defer CloseTerm()
-	keyboard := GenKeyboardEventQueue()
-	var gameOver bool = false
-mainLoop:
-	for !gameOver {
-		ClearConsole()
-		OutputBoard(CurrentBoard)
-		key := KeyPressed(keyboard, true)
-		switch key {
-		case Right:
-			snakeSetRight()
-		case Left:
-			snakeSetLeft()
-		case Up:
-			snakeSetUp()
-		case Down:
-			snakeSetDown()
-		case Esc:
-			break mainLoop
-		}
-		gameOver = snakeProceed()
-	}
-	score--
-	return score
+/*PlayAlone lets the computer play 1 default game and returns the game sequence*/
+func PlayAlone(verboseFlag bool, game *GameStatus) GameSequence {
+	return defaultGame.PlayAlone(verboseFlag, game)
 }
 
-func generateFruits(game *GameStatus) {
-	for i := 0; i < MaxGameScore; i++ {
-		game.fruits[i].x, game.fruits[i].y = getNewFruitCoords(&startingBoard)
-	}
+/*PlayAlone lets the agent of the game play 1 game and returns the game sequence*/
+func (g *Game) PlayAlone(verboseFlag bool, game *GameStatus) GameSequence {
+	return g.playAgent(g.agent, verboseFlag, game)
 }
 
-/*GenerateGameParams generates a configuration fot the game*/
-func GenerateGameParams() GameStatus {
-	var status GameStatus
-	status.fruits = make([]Coord, MaxGameScore)
-	generateFruits(&status)
-	return status
+/*GetRandomSolution generates a solution on the default game*/
+func GetRandomSolution(game *GameStatus) (GameSequence, int) {
+	return defaultGame.GetRandomSolution(game)
 }
 
-/*PlayAlone lets the computer play 1 game and returns the game sequence*/
-func PlayAlone(verboseFlag bool, game *GameStatus) GameSequence {
-	gameOver := false
-	//var currentGameSequence GameSequence
-	//currentGameSequence = make([]int, MaxGameSequenceLength)
-	currentGameSequence := []int{}
-	i := 0
-	for !gameOver && i < MaxGameSequenceLength {
-		if verboseFlag {
-			ClearConsole()
-			OutputBoard(CurrentBoard)
-		}
-		direction = getRandomValidMove()
-		switch direction {
-		case Up:
-			fmt.Print("U")
-		case Down:
-			fmt.Print("D")
-		case Right:
-			fmt.Print("R")
-		case Left:
-			fmt.Print("L")
-		}
-		gameOver = snakeProceedGivenMatch(game)
-		//currentGameSequence[i] = direction
-		currentGameSequence = append(currentGameSequence, direction)
-		i++
-	}
-	score--
-	/*fmt.Println()
-	fmt.Println("Final score: ", score)
-	fmt.Println()*/
-	return currentGameSequence
-}
-
-/*getRandomValidMove returns a valid move. Here Valid doesn't mean the snake won't die*/
-func getRandomValidMove() int {
-	var randDirection int
-findValidDir:
-	for {
-		randDirection = rand.Intn(4) + 1
-		if canGo, what := snakeCanGoDirection(randDirection); canGo {
-			break findValidDir
-		} else {
-			if what != Neck {
-				break findValidDir
-			}
-		}
-	}
-	return randDirection
+/*
+GetRandomSolution generates a solution by letting the agent of the game play,
+random moves unless SetAgent chose another one, until the snake dies
+*/
+func (g *Game) GetRandomSolution(game *GameStatus) (GameSequence, int) {
+	gameSequence := g.PlayAlone(false, game)
+	return gameSequence, g.score
 }
 
-/*GetRandomSolution generates a solution by playing a game by choosing random moves
-until the snake dies
-*/
-func GetRandomSolution(game *GameStatus) (GameSequence, int) {
-	gameSequence := PlayAlone(false, game)
-	return gameSequence, score
+/*GetContinuingSolution continue the default game starting from the given sequence*/
+func GetContinuingSolution(game *GameStatus, gameSequence *GameSequence, prevScore int) (GameSequence, int) {
+	return defaultGame.GetContinuingSolution(game, gameSequence, prevScore)
 }
 
 /*GetContinuingSolution continue the game starting from the given sequence*/
-func GetContinuingSolution(game *GameStatus, gameSequence *GameSequence, prevScore int) (GameSequence, int) {
-	score = prevScore
-	newGameSequence := ReplayGame(false, game, gameSequence)
-	return newGameSequence, score
+func (g *Game) GetContinuingSolution(game *GameStatus, gameSequence *GameSequence, prevScore int) (GameSequence, int) {
+	g.score = prevScore
+	newGameSequence := g.ReplayGame(false, game, gameSequence)
+	return newGameSequence, g.score
 }
 
-/*ReplayGame lets the computer play 1 game and returns the game sequence*/
+/*ReplayGame lets the computer play 1 default game and returns the game sequence*/
 func ReplayGame(verboseFlag bool, game *GameStatus, inputGameSequence *GameSequence) GameSequence {
-	gameOver := false
-	//var currentGameSequence GameSequence
-	//currentGameSequence = make([]int, MaxGameSequenceLength)
-	currentGameSequence := []int{}
-	currentGameSequence = append(currentGameSequence, *inputGameSequence...)
-	i := 0
-	for !gameOver && i < MaxGameSequenceLength {
-		if verboseFlag {
-			ClearConsole()
-			OutputBoard(CurrentBoard)
-		}
-
-		if i < len(*inputGameSequence) {
-			direction = (*inputGameSequence)[i]
-		} else {
-			direction = getRandomValidMove()
-		}
+	return defaultGame.ReplayGame(verboseFlag, game, inputGameSequence)
+}
 
-		switch direction {
-		case Up:
-			fmt.Print("U")
-		case Down:
-			fmt.Print("D")
-		case Right:
-			fmt.Print("R")
-		case Left:
-			fmt.Print("L")
-		}
-		//gameOver = snakeProceedGivenMatch(game)
-		gameOver = snakeProceed()
-		currentGameSequence = append(currentGameSequence, direction)
-		i++
-	}
-	score--
-	/*fmt.Println()
-	fmt.Println("Final score: ", score)
-	fmt.Println()*/
-	return currentGameSequence
+/*ReplayGame replays the sequence, then lets the agent of the game play until the end, and returns the game sequence*/
+func (g *Game) ReplayGame(verboseFlag bool, game *GameStatus, inputGameSequence *GameSequence) GameSequence {
+	return g.playAgent(&ReplayAgent{Moves: *inputGameSequence, Then: g.agent}, verboseFlag, game)
 }
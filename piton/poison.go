@@ -0,0 +1,108 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package piton
+
+/*PoisonKills makes the snake die when it eats a poison*/
+const PoisonKills = 1
+
+/*PoisonShrinks makes the snake lose Amount segments of its tail when it eats a poison*/
+const PoisonShrinks = 2
+
+/*PoisonPenalty makes the player lose Amount points when the snake eats a poison*/
+const PoisonPenalty = 3
+
+/*
+PoisonConfig tells what happens when the snake eats a poison and how poisons appear.
+Every move a new poison appears on a random empty cell with a probability of Chance percent,
+as long as there are less than Max poisons on the board, Max left to zero is no limit.
+Rule left to zero is PoisonKills, Amount left to zero is 1
+*/
+type PoisonConfig struct {
+	Rule   int
+	Amount int
+	Chance int
+	Max    int
+}
+
+type scheduledPoison struct {
+	move int
+	at   Coord
+}
+
+/*SchedulePoison makes a poison appear on the x, y cell after the given number of moves, if the cell is on the board and empty*/
+func (status *GameStatus) SchedulePoison(move int, x int, y int) {
+	status.poisons = append(status.poisons, scheduledPoison{move, Coord{x, y}})
+}
+
+func (c PoisonConfig) withDefaults() PoisonConfig {
+	if c.Rule == 0 {
+		c.Rule = PoisonKills
+	}
+	if c.Amount == 0 {
+		c.Amount = 1
+	}
+	return c
+}
+
+/*eatPoison applies the poison rule after the head moved on a poison, it returns true if the snake dies*/
+func (g *Game) eatPoison() bool {
+	g.poisonCount--
+	switch g.config.Poison.Rule {
+	case PoisonShrinks:
+		if g.body.len() <= g.config.Poison.Amount {
+			return true
+		}
+		for i := 0; i < g.config.Poison.Amount; i++ {
+			tail := g.body.popTail()
+			g.board[tail.y][tail.x] = Empty
+		}
+	case PoisonPenalty:
+		g.score -= g.config.Poison.Amount
+		if g.score < 0 {
+			g.score = 0
+		}
+	default:
+		return true
+	}
+	return false
+}
+
+/*spawnPoisons puts on the board the poisons scheduled for this move and the random ones*/
+func (g *Game) spawnPoisons(status *GameStatus) {
+	if status != nil {
+		for _, poison := range status.poisons {
+			if poison.move == g.moves && g.config.isPlayable(poison.at) && isEmpty(&g.board, poison.at.x, poison.at.y) {
+				g.board[poison.at.y][poison.at.x] = Poison
+				g.poisonCount++
+			}
+		}
+	}
+	poison := g.config.Poison
+	if poison.Chance > 0 && (poison.Max == 0 || g.poisonCount < poison.Max) && g.rng.Intn(100) < poison.Chance {
+		x, y := g.getRandomEmptyCell(&g.board)
+		if x != -1 && y != -1 {
+			g.board[y][x] = Poison
+			g.poisonCount++
+		}
+	}
+}
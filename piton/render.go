@@ -0,0 +1,344 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package piton
+
+import (
+	"bufio"
+	"io"
+
+	term "github.com/nsf/termbox-go"
+)
+
+/*
+Renderer shows a game. Every frame starts with Clear when the screen has to be drawn from scratch,
+then Board draws the board, Move tells where the snake goes next and Flush shows the frame
+*/
+type Renderer interface {
+	Clear()
+	Board(board BoardType)
+	Move(direction int)
+	Flush()
+}
+
+/*
+TermRenderer draws the game full screen with termbox, InitTerm has to be called before using it.
+It remembers what every cell shows, so a new frame only changes the cells that differ
+from the last one and the screen doesn't flicker
+*/
+type TermRenderer struct {
+	theme *Theme
+	shown [][]cell
+	view  camera
+}
+
+/*cell is what a cell of the screen shows*/
+type cell struct {
+	ch rune
+	fg Color
+	bg Color
+}
+
+/*NewTermRenderer creates a renderer that draws with termbox in the classic theme*/
+func NewTermRenderer() *TermRenderer {
+	return &TermRenderer{theme: &themes[0]}
+}
+
+/*SetTheme sets how the renderer draws the board, what the terminal can't show is replaced by what it can*/
+func (r *TermRenderer) SetTheme(t *Theme) {
+	r.theme = t.Degrade(DetectTerminal())
+	r.shown = nil
+}
+
+/*Clear makes the next frame redraw the whole screen, after something else was drawn over it*/
+func (r *TermRenderer) Clear() {
+	r.shown = nil
+}
+
+/*Board draws the cells of the board that changed since the last frame*/
+func (r *TermRenderer) Board(board BoardType) {
+	r.drawCells(r.theme.boardCells(board))
+}
+
+/*drawCells draws the cells that changed since the last frame*/
+func (r *TermRenderer) drawCells(cells [][]cell) {
+	if !r.fits(cells) {
+		term.Clear(term.ColorDefault, term.ColorDefault)
+		r.shown = make([][]cell, len(cells))
+		for y, row := range cells {
+			r.shown[y] = make([]cell, len(row))
+		}
+	}
+	for y, row := range cells {
+		for x, c := range row {
+			if r.shown[y][x] != c {
+				term.SetCell(x, y, c.ch, term.Attribute(c.fg), term.Attribute(c.bg))
+				r.shown[y][x] = c
+			}
+		}
+	}
+}
+
+/*Move does nothing, the board already shows where the snake is*/
+func (r *TermRenderer) Move(direction int) {
+}
+
+/*Flush shows the frame on the terminal*/
+func (r *TermRenderer) Flush() {
+	term.Flush()
+}
+
+/*fits tells if the remembered frame has the size of the new one*/
+func (r *TermRenderer) fits(cells [][]cell) bool {
+	if len(r.shown) != len(cells) {
+		return false
+	}
+	for y, row := range cells {
+		if len(r.shown[y]) != len(row) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+TextRenderer writes the game as plain text, for logs and pipes: the board one row per line
+and the moves as the letters U, D, R and L. Clear writes the ANSI codes that clear the terminal
+*/
+type TextRenderer struct {
+	w *bufio.Writer
+}
+
+/*NewTextRenderer creates a renderer that writes to w*/
+func NewTextRenderer(w io.Writer) *TextRenderer {
+	return &TextRenderer{bufio.NewWriter(w)}
+}
+
+/*Clear writes the ANSI codes that clear the terminal*/
+func (r *TextRenderer) Clear() {
+	r.w.WriteString("\033[H\033[2J")
+}
+
+/*Board writes the board without the wall around it*/
+func (r *TextRenderer) Board(board BoardType) {
+	writeBoard(r.w, board)
+}
+
+/*Move writes the letter of the direction*/
+func (r *TextRenderer) Move(direction int) {
+	if letter := moveLetter(direction); letter != 0 {
+		r.w.WriteRune(letter)
+	}
+}
+
+/*Flush writes what is still buffered*/
+func (r *TextRenderer) Flush() {
+	r.w.Flush()
+}
+
+/*NullRenderer shows nothing, to play games as fast as possible*/
+type NullRenderer struct{}
+
+/*Clear does nothing*/
+func (NullRenderer) Clear() {}
+
+/*Board does nothing*/
+func (NullRenderer) Board(board BoardType) {}
+
+/*Move does nothing*/
+func (NullRenderer) Move(direction int) {}
+
+/*Flush does nothing*/
+func (NullRenderer) Flush() {}
+
+/*SetRenderer sets how the default game is shown*/
+func SetRenderer(r Renderer) {
+	defaultGame.SetRenderer(r)
+}
+
+/*SetRenderer sets how PlayAlone and ReplayGame show the game, nil shows nothing*/
+func (g *Game) SetRenderer(r Renderer) {
+	if r == nil {
+		r = NullRenderer{}
+	}
+	g.renderer = r
+}
+
+/*drawFrame draws the board and shows it*/
+func drawFrame(r Renderer, board BoardType) {
+	r.Board(board)
+	r.Flush()
+}
+
+/*writeBoard writes the board, one row per line, without the wall around it, the walls inside it as '#'*/
+func writeBoard(w io.Writer, board BoardType) {
+	for y := 1; y < len(board)-1; y++ {
+		row := board[y]
+		line := make([]rune, 0, len(row))
+		for x := 1; x < len(row)-1; x++ {
+			line = append(line, cellGlyph(row[x]))
+		}
+		io.WriteString(w, string(line)+"\n")
+	}
+}
+
+/*moveLetter returns the letter of a direction, 0 if it isn't one*/
+func moveLetter(direction int) rune {
+	switch direction {
+	case Up:
+		return 'U'
+	case Down:
+		return 'D'
+	case Right:
+		return 'R'
+	case Left:
+		return 'L'
+	}
+	return 0
+}
+
+/*boardCells returns how the cells of the board look in the theme, every segment of the body the same*/
+func (t *Theme) boardCells(board BoardType) [][]cell {
+	cells := make([][]cell, len(board))
+	for y, row := range board {
+		cells[y] = make([]cell, len(row))
+		for x, value := range row {
+			cells[y][x] = t.cellOf(value)
+		}
+	}
+	return cells
+}
+
+/*cellOf returns how a cell with the value looks*/
+func (t *Theme) cellOf(value int) cell {
+	var glyph Glyph
+	switch {
+	case value == Wall:
+		glyph = t.Wall
+	case value == Empty:
+		glyph = t.Empty
+	case value == Snake:
+		glyph = t.Head
+	case value > Snake:
+		glyph = t.Body
+	case value == Fruit:
+		glyph = t.Fruit
+	case value == GoldenFruit:
+		glyph = t.Golden
+	case value == TimedFruit:
+		glyph = t.Timed
+	case value == Poison:
+		glyph = t.Poison
+	default:
+		return cell{ch: ' '}
+	}
+	return glyph.cell(0)
+}
+
+func (g Glyph) cell(i int) cell {
+	return cell{g.char(i), g.Fg, g.Bg}
+}
+
+/*
+cells returns how the board of the game looks in the theme, with the head and the tail
+pointing where the snake goes and the body bending where it turns
+*/
+func (g *Game) cells(t *Theme) [][]cell {
+	cells := t.boardCells(g.board)
+	n := g.body.len()
+	for i := 0; i < n; i++ {
+		at := g.body.at(i)
+		var c cell
+		switch {
+		case i == 0 && n == 1:
+			c = t.Head.cell(g.heading() - Right)
+		case i == 0:
+			c = t.Head.cell(g.towards(g.body.at(1), at) - Right)
+		case i == n-1:
+			c = t.Tail.cell(g.towards(at, g.body.at(i-1)) - Right)
+		default:
+			c = t.Body.cell(bodyShape(g.towards(at, g.body.at(i-1)), g.towards(at, g.body.at(i+1))))
+		}
+		cells[at.y][at.x] = c
+	}
+	return cells
+}
+
+/*towards returns the direction that goes from a to the next cell b, none if they aren't next to each other*/
+func (g *Game) towards(a Coord, b Coord) int {
+	for direction := Right; direction <= Down; direction++ {
+		if g.neighbor(a, direction) == b {
+			return direction
+		}
+	}
+	return none
+}
+
+/*bodyShape returns which of the six body glyphs joins a segment to the ones in the two directions*/
+func bodyShape(a int, b int) int {
+	horizontal := func(d int) bool { return d == Left || d == Right }
+	has := func(d int) bool { return a == d || b == d }
+	switch {
+	case horizontal(a) && horizontal(b):
+		return 0
+	case !horizontal(a) && !horizontal(b):
+		return 1
+	case has(Up) && has(Right):
+		return 2
+	case has(Up) && has(Left):
+		return 3
+	case has(Down) && has(Right):
+		return 4
+	case has(Down) && has(Left):
+		return 5
+	}
+	return 0
+}
+
+/*cellGlyph returns the character printed for a cell*/
+func cellGlyph(cell int) rune {
+	switch {
+	case cell == Wall:
+		return '#'
+	case cell == Empty:
+		return '.'
+	case cell == Snake:
+		return '@'
+	case cell > Snake:
+		return 'O'
+	case cell == Fruit:
+		return 'F'
+	case cell == GoldenFruit:
+		return 'G'
+	case cell == TimedFruit:
+		return 'T'
+	case cell == Poison:
+		return 'P'
+	}
+	return ' '
+}
+
+func drawText(x int, y int, text string, fg term.Attribute, bg term.Attribute) {
+	for i, ch := range []rune(text) {
+		term.SetCell(x+i, y, ch, fg, bg)
+	}
+}
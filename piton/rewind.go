@@ -0,0 +1,100 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package piton
+
+/*rewindRing keeps the snapshots of the last ticks, the oldest one is overwritten when it is full*/
+type rewindRing struct {
+	snapshots []*savedGame
+	next      int
+	count     int
+}
+
+func newRewindRing(size int) *rewindRing {
+	return &rewindRing{snapshots: make([]*savedGame, size)}
+}
+
+func (r *rewindRing) push(snapshot *savedGame) {
+	if len(r.snapshots) == 0 {
+		return
+	}
+	r.snapshots[r.next] = snapshot
+	r.next = (r.next + 1) % len(r.snapshots)
+	if r.count < len(r.snapshots) {
+		r.count++
+	}
+}
+
+/*oldest takes out the oldest snapshot and forgets all the others, it returns nil when the ring is empty*/
+func (r *rewindRing) oldest() *savedGame {
+	if r.count == 0 {
+		return nil
+	}
+	first := (r.next - r.count + len(r.snapshots)) % len(r.snapshots)
+	snapshot := r.snapshots[first]
+	r.clear()
+	return snapshot
+}
+
+func (r *rewindRing) clear() {
+	for i := range r.snapshots {
+		r.snapshots[i] = nil
+	}
+	r.next, r.count = 0, 0
+}
+
+/*SetRewind makes the default game a practice game where Backspace takes back the last ticks*/
+func SetRewind(ticks int) {
+	defaultGame.SetRewind(ticks)
+}
+
+/*
+SetRewind makes HumanPlay keep the last ticks so that Backspace takes the game back that many ticks,
+even after the snake died. 0 turns rewinding off
+*/
+func (g *Game) SetRewind(ticks int) {
+	if ticks < 0 {
+		ticks = 0
+	}
+	g.rewindTicks = ticks
+}
+
+/*Practice tells if the default game was rewound*/
+func Practice() bool {
+	return defaultGame.Practice()
+}
+
+/*Practice tells if the game was rewound, so its score doesn't count as a real one*/
+func (g *Game) Practice() bool {
+	return g.practice
+}
+
+/*rewind takes the game back to the oldest snapshot of the ring, it returns false if there is none*/
+func (g *Game) rewind(history *rewindRing) bool {
+	snapshot := history.oldest()
+	if snapshot == nil {
+		return false
+	}
+	g.restore(snapshot)
+	g.practice = true
+	return true
+}
@@ -0,0 +1,314 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package piton
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+)
+
+/*saveVersion is the version of the format of the saved games*/
+const saveVersion = 1
+
+/*
+countingSource is a random source that counts how many numbers it gave,
+so that it can be brought back to the same state starting again from the seed
+*/
+type countingSource struct {
+	source rand.Source64
+	count  int64
+}
+
+func newCountingSource(seed int64) *countingSource {
+	return &countingSource{source: rand.NewSource(seed).(rand.Source64)}
+}
+
+func (s *countingSource) Int63() int64 {
+	s.count++
+	return s.source.Int63()
+}
+
+func (s *countingSource) Uint64() uint64 {
+	s.count++
+	return s.source.Uint64()
+}
+
+func (s *countingSource) Seed(seed int64) {
+	s.source.Seed(seed)
+	s.count = 0
+}
+
+/*skip throws away numbers until the source gave count of them*/
+func (s *countingSource) skip(count int64) {
+	for s.count < count {
+		s.Int63()
+	}
+}
+
+type savedFruit struct {
+	At      Coord
+	Kind    int
+	Expires int
+	Fixed   bool
+}
+
+type savedScheduledPoison struct {
+	Move int
+	At   Coord
+}
+
+type savedStatus struct {
+	Seed    int64
+	Fruits  []savedFruit
+	Poisons []savedScheduledPoison
+}
+
+/*savedGame is what is written in a save file*/
+type savedGame struct {
+	Version           int
+	Config            BoardConfig
+	Difficulty        Difficulty
+	Board             BoardType
+	Body              []Coord
+	Fruits            []savedFruit
+	Growth            int
+	Direction         int
+	Turns             []int
+	CurrentFruitIndex int
+	Moves             int
+	PoisonCount       int
+	Score             int
+	Death             int
+	Won               bool
+	Practice          bool
+	Sequence          GameSequence
+	Seed              int64
+	RandomCalls       int64
+	MoveRandomCalls   int64
+	Status            *savedStatus
+}
+
+/*MarshalJSON writes the cell as [x, y]*/
+func (c Coord) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]int{c.x, c.y})
+}
+
+/*UnmarshalJSON reads a cell written as [x, y]*/
+func (c *Coord) UnmarshalJSON(data []byte) error {
+	var xy [2]int
+	if err := json.Unmarshal(data, &xy); err != nil {
+		return err
+	}
+	c.x, c.y = xy[0], xy[1]
+	return nil
+}
+
+/*Save writes the default game to w*/
+func Save(w io.Writer) error {
+	return defaultGame.Save(w)
+}
+
+/*Save writes everything about the game to w, to go on playing it later with Load*/
+func (g *Game) Save(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", " ")
+	return encoder.Encode(g.snapshot())
+}
+
+/*Load reads the default game from r*/
+func Load(r io.Reader) error {
+	return defaultGame.Load(r)
+}
+
+/*
+Load reads a game written by Save and puts the game back in the same state,
+random sources included, so it goes on as if it was never interrupted.
+The game is left untouched when r doesn't hold a valid saved game
+*/
+func (g *Game) Load(r io.Reader) error {
+	var saved savedGame
+	if err := json.NewDecoder(r).Decode(&saved); err != nil {
+		return err
+	}
+	if saved.Version != saveVersion {
+		return fmt.Errorf("unknown save version %d", saved.Version)
+	}
+	if err := saved.check(); err != nil {
+		return err
+	}
+	g.restore(&saved)
+	return nil
+}
+
+/*snapshot copies the state of the game, nothing in it is shared with the game*/
+func (g *Game) snapshot() *savedGame {
+	saved := &savedGame{
+		Version:           saveVersion,
+		Config:            g.config,
+		Difficulty:        g.difficulty,
+		Board:             copyBoard(g.board),
+		Body:              make([]Coord, g.body.len()),
+		Growth:            g.growth,
+		Direction:         g.direction,
+		Turns:             append([]int{}, g.turns...),
+		CurrentFruitIndex: g.currentFruitIndex,
+		Moves:             g.moves,
+		PoisonCount:       g.poisonCount,
+		Score:             g.score,
+		Death:             g.death,
+		Won:               g.won,
+		Practice:          g.practice,
+		Sequence:          append(GameSequence{}, g.sequence...),
+		Seed:              g.seed,
+		RandomCalls:       g.rngSource.count,
+		MoveRandomCalls:   g.moveSource.count,
+	}
+	for i := range saved.Body {
+		saved.Body[i] = g.body.at(i)
+	}
+	for _, f := range g.fruits {
+		saved.Fruits = append(saved.Fruits, savedFruit{f.at, f.kind, f.expires, f.fixed})
+	}
+	if g.status != nil {
+		saved.Status = &savedStatus{Seed: g.status.Seed}
+		for _, f := range g.status.fruits {
+			saved.Status.Fruits = append(saved.Status.Fruits, savedFruit{At: f.at, Kind: f.kind})
+		}
+		for _, p := range g.status.poisons {
+			saved.Status.Poisons = append(saved.Status.Poisons, savedScheduledPoison{p.move, p.at})
+		}
+	}
+	return saved
+}
+
+/*restore puts the game back in the state of the snapshot, which can be restored again later*/
+func (g *Game) restore(saved *savedGame) {
+	g.config = saved.Config
+	g.difficulty = saved.Difficulty
+	g.board = copyBoard(saved.Board)
+	g.startingBoard = g.board
+	g.syncCurrentBoard()
+	g.body = newSnakeBody(g.config.Width * g.config.Height)
+	for i := len(saved.Body) - 1; i >= 0; i-- {
+		g.body.pushHead(saved.Body[i])
+	}
+	g.fruits = g.fruits[:0]
+	for _, f := range saved.Fruits {
+		g.fruits = append(g.fruits, fruit{f.At, f.Kind, f.Expires, f.Fixed})
+	}
+	g.growth = saved.Growth
+	g.direction = saved.Direction
+	g.turns = append(g.turns[:0], saved.Turns...)
+	g.currentFruitIndex = saved.CurrentFruitIndex
+	g.moves = saved.Moves
+	g.poisonCount = saved.PoisonCount
+	g.score = saved.Score
+	g.death = saved.Death
+	g.won = saved.Won
+	g.practice = saved.Practice
+	g.sequence = append(GameSequence{}, saved.Sequence...)
+	g.status = nil
+	if saved.Status != nil {
+		g.status = &GameStatus{Seed: saved.Status.Seed}
+		for _, f := range saved.Status.Fruits {
+			g.status.fruits = append(g.status.fruits, scheduledFruit{f.At, f.Kind})
+		}
+		for _, p := range saved.Status.Poisons {
+			g.status.poisons = append(g.status.poisons, scheduledPoison{p.Move, p.At})
+		}
+	}
+	g.setSeed(saved.Seed)
+	g.rngSource.skip(saved.RandomCalls)
+	g.moveSource.skip(saved.MoveRandomCalls)
+}
+
+func copyBoard(board BoardType) BoardType {
+	board = append(BoardType{}, board...)
+	for y := range board {
+		board[y] = append([]int{}, board[y]...)
+	}
+	return board
+}
+
+/*check tells if the saved game can be played, so that a broken file doesn't make the game panic*/
+func (saved *savedGame) check() error {
+	if err := saved.Config.Check(); err != nil {
+		return err
+	}
+	if saved.Difficulty.Tick <= 0 {
+		return errors.New("the difficulty has no tick")
+	}
+	w, h := saved.Config.Width, saved.Config.Height
+	if len(saved.Board) != h+2 {
+		return fmt.Errorf("the board has %d rows instead of %d", len(saved.Board), h+2)
+	}
+	for y, row := range saved.Board {
+		if len(row) != w+2 {
+			return fmt.Errorf("row %d of the board has %d cells instead of %d", y, len(row), w+2)
+		}
+	}
+	if len(saved.Body) == 0 {
+		return errors.New("the snake has no body")
+	}
+	if len(saved.Body) > w*h {
+		return fmt.Errorf("the snake has %d segments, more than the %d cells of the board", len(saved.Body), w*h)
+	}
+	probe := &Game{config: saved.Config}
+	for i, c := range saved.Body {
+		if !saved.Config.isPlayable(c) {
+			return fmt.Errorf("the snake is out of the board at %d, %d", c.x, c.y)
+		}
+		if i > 0 && !probe.nextTo(saved.Body[i-1], c) {
+			return fmt.Errorf("the snake is broken between %d, %d and %d, %d", saved.Body[i-1].x, saved.Body[i-1].y, c.x, c.y)
+		}
+	}
+	for _, f := range saved.Fruits {
+		if !saved.Config.isPlayable(f.At) {
+			return fmt.Errorf("a fruit is out of the board at %d, %d", f.At.x, f.At.y)
+		}
+	}
+	if saved.Status != nil {
+		for _, p := range saved.Status.Poisons {
+			if !saved.Config.isPlayable(p.At) {
+				return fmt.Errorf("a poison is scheduled out of the board at %d, %d", p.At.x, p.At.y)
+			}
+		}
+	}
+	if saved.RandomCalls < 0 || saved.MoveRandomCalls < 0 {
+		return errors.New("negative random state")
+	}
+	return nil
+}
+
+/*nextTo tells if b is the cell next to a in one of the four directions*/
+func (g *Game) nextTo(a Coord, b Coord) bool {
+	for direction := Right; direction <= Down; direction++ {
+		if g.neighbor(a, direction) == b {
+			return true
+		}
+	}
+	return false
+}
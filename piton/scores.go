@@ -0,0 +1,115 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package piton
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+)
+
+/*
+ScoreBook keeps the best score made on every board. Boards are told apart by their config,
+so every level and every set of modifiers has its own best score
+*/
+type ScoreBook struct {
+	Best map[string]int
+}
+
+/*NewScoreBook creates an empty score book*/
+func NewScoreBook() *ScoreBook {
+	return &ScoreBook{Best: map[string]int{}}
+}
+
+/*LoadScoreBook reads a score book written by Save*/
+func LoadScoreBook(r io.Reader) (*ScoreBook, error) {
+	book := NewScoreBook()
+	if err := json.NewDecoder(r).Decode(book); err != nil {
+		return nil, err
+	}
+	if book.Best == nil {
+		book.Best = map[string]int{}
+	}
+	return book, nil
+}
+
+/*Save writes the score book to w*/
+func (b *ScoreBook) Save(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", " ")
+	return encoder.Encode(b)
+}
+
+/*BestScore returns the best score made on the board, 0 if it was never played*/
+func (b *ScoreBook) BestScore(config BoardConfig) int {
+	return b.Best[BoardKey(config)]
+}
+
+/*Record keeps the score if it is the best one made on the board, it returns true if it is*/
+func (b *ScoreBook) Record(config BoardConfig, score int) bool {
+	key := BoardKey(config)
+	if best, found := b.Best[key]; found && score <= best {
+		return false
+	}
+	b.Best[key] = score
+	return true
+}
+
+/*BoardKey returns the name a board has in the score book*/
+func BoardKey(config BoardConfig) string {
+	config = config.withDefaults()
+	data, err := json.Marshal(config)
+	if err != nil {
+		panic(err)
+	}
+	hash := fnv.New64a()
+	hash.Write(data)
+	return fmt.Sprintf("%dx%d-%016x", config.Width, config.Height, hash.Sum64())
+}
+
+/*SetScoreBook sets where the best scores of the default game are kept*/
+func SetScoreBook(book *ScoreBook) {
+	defaultGame.SetScoreBook(book)
+}
+
+/*SetScoreBook sets where HumanPlay looks up and records the best scores, practice games are never recorded*/
+func (g *Game) SetScoreBook(book *ScoreBook) {
+	g.scoreBook = book
+}
+
+/*bestScore returns the best score made on the board of the game*/
+func (g *Game) bestScore() int {
+	if g.scoreBook == nil {
+		return 0
+	}
+	return g.scoreBook.BestScore(g.config)
+}
+
+/*recordScore puts the score in the score book, unless it is a practice game, and returns true if it is a new best*/
+func (g *Game) recordScore() bool {
+	if g.scoreBook == nil || g.practice {
+		return false
+	}
+	return g.scoreBook.Record(g.config, g.score)
+}
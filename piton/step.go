@@ -0,0 +1,180 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package piton
+
+/*DeathNone means the snake is alive*/
+const DeathNone = 0
+
+/*DeathWall means the snake hit a wall*/
+const DeathWall = 1
+
+/*DeathBody means the snake hit its own body*/
+const DeathBody = 2
+
+/*DeathPoison means the snake ate a deadly poison*/
+const DeathPoison = 3
+
+/*DeathNoMoves means the snake made the MaxMoves moves of the board config, or as many as an agent can make*/
+const DeathNoMoves = 4
+
+/*
+StepResult tells what happened during one move.
+FruitKind is the kind of the fruit eaten, Segment is the segment of the body the snake hit,
+1 being the neck and Length-1 the tail.
+Blocked is true when the snake didn't move because it was asked to go back on its neck.
+Won is true when the score reached the target or the snake left no room for another fruit
+*/
+type StepResult struct {
+	Moved       bool
+	Blocked     bool
+	AteFruit    bool
+	FruitKind   int
+	HitPoison   bool
+	Died        bool
+	Death       int
+	Segment     int
+	ScoreDelta  int
+	LengthDelta int
+	Score       int
+	Length      int
+	Won         bool
+	GameOver    bool
+}
+
+/*Step moves the snake of the default game one cell in the given direction*/
+func Step(direction int) StepResult {
+	return defaultGame.Step(direction)
+}
+
+/*
+Step moves the snake one cell in the given direction and tells what happened.
+A direction that is not Right, Left, Up or Down keeps the snake going where it was going
+*/
+func (g *Game) Step(direction int) StepResult {
+	if direction >= Right && direction <= Down {
+		g.direction = direction
+	}
+	return g.proceed(g.status)
+}
+
+/*Won tells if the player of the default game won*/
+func Won() bool {
+	return defaultGame.Won()
+}
+
+/*Won tells if the player won the game*/
+func (g *Game) Won() bool {
+	return g.won
+}
+
+/*DeathCause returns why the snake died, DeathNone while it is alive*/
+func (g *Game) DeathCause() int {
+	return g.death
+}
+
+/*Length returns how many segments the snake has*/
+func (g *Game) Length() int {
+	return g.body.len()
+}
+
+/*proceed moves the snake one cell in its direction, status gives the scheduled fruits and poisons*/
+func (g *Game) proceed(status *GameStatus) StepResult {
+	result := StepResult{Score: g.score, Length: g.body.len()}
+	if g.death != DeathNone || g.won {
+		result.Died, result.Death, result.Won, result.GameOver = g.death != DeathNone, g.death, g.won, true
+		return result
+	}
+	if g.direction < Right || g.direction > Down {
+		return result
+	}
+	g.sequence = append(g.sequence, g.direction)
+	next := g.neighbor(g.body.headCoord(), g.direction)
+	if canGo, what := g.snakeCanGoDirection(g.direction); canGo {
+		if what == Fruit {
+			result.AteFruit = true
+			result.FruitKind = g.board[next.y][next.x]
+			g.eatFruit(next)
+		}
+		g.advanceSnake(g.takeGrowth())
+		result.Moved = true
+		if what == Poison {
+			result.HitPoison = true
+			if g.eatPoison() {
+				g.snakeDies(DeathPoison)
+			}
+		}
+	} else {
+		if what == Neck {
+			result.Blocked = true
+		} else if what == Wall {
+			g.snakeDies(DeathWall)
+		} else {
+			result.Segment = g.segmentAt(next)
+			g.snakeDies(DeathBody)
+		}
+	}
+	g.moves++
+	if g.death == DeathNone && g.config.MaxMoves > 0 && g.moves >= g.config.MaxMoves {
+		g.snakeDies(DeathNoMoves)
+	}
+	if g.death == DeathNone {
+		g.expireFruits()
+		g.refillFruits(status)
+		g.spawnPoisons(status)
+		if g.reachedTarget() || len(g.fruits) == 0 {
+			g.won = true
+		}
+	}
+
+	result.ScoreDelta = g.score - result.Score
+	result.LengthDelta = g.body.len() - result.Length
+	result.Score = g.score
+	result.Length = g.body.len()
+	if g.death != DeathNone {
+		result.Died, result.Death, result.GameOver = true, g.death, true
+	}
+	if g.won {
+		result.Won, result.GameOver = true, true
+	}
+	return result
+}
+
+/*reachedTarget tells if the score reached the target score of the board, never when the board has none*/
+func (g *Game) reachedTarget() bool {
+	return g.config.TargetScore > 0 && g.score >= g.config.TargetScore
+}
+
+/*Sequence returns the directions the snake moved to since the start of the game*/
+func (g *Game) Sequence() GameSequence {
+	return append(GameSequence{}, g.sequence...)
+}
+
+/*segmentAt returns which segment of the body is on the cell, 0 being the head, -1 if none*/
+func (g *Game) segmentAt(c Coord) int {
+	for i := 0; i < g.body.len(); i++ {
+		if g.body.at(i) == c {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,374 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package piton
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+A theme file is plain text made of "key: value" lines, lines starting with ';' are comments.
+The name key gives the name of the theme, every other key sets the glyphs and the colors of a part of the board:
+
+	empty wall head body tail fruit golden timed poison
+
+The value is the glyphs followed by the foreground and the background color, the colors may be left out:
+
+	head: ><^v green default
+
+head and tail take one glyph or four, for a snake going right, left, up and down.
+body takes one glyph or six: horizontal, vertical, then the corners joining up and right,
+up and left, down and right, down and left. The word space stands for a blank glyph.
+A color is default, one of black red green yellow blue magenta cyan white,
+or a number from 0 to 255 of the 256 colors palette.
+*/
+
+/*Color is a color of the 256 colors palette plus one, 0 being the default color of the terminal*/
+type Color int
+
+/*ColorDefault is the color the terminal uses when none is chosen*/
+const ColorDefault Color = 0
+
+var colorNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+/*Glyph tells how a part of the board looks: the characters it is drawn with and its colors*/
+type Glyph struct {
+	Chars string
+	Fg    Color
+	Bg    Color
+}
+
+/*Theme tells how every part of the board looks*/
+type Theme struct {
+	Name   string
+	Empty  Glyph
+	Wall   Glyph
+	Head   Glyph
+	Body   Glyph
+	Tail   Glyph
+	Fruit  Glyph
+	Golden Glyph
+	Timed  Glyph
+	Poison Glyph
+}
+
+/*TerminalSupport tells what the terminal can show: Unicode glyphs and how many colors, 0, 8 or 256*/
+type TerminalSupport struct {
+	Unicode bool
+	Colors  int
+}
+
+func palette(n int) Color {
+	return Color(n + 1)
+}
+
+var themes = []Theme{
+	{
+		Name:   "classic",
+		Empty:  Glyph{Chars: "."},
+		Wall:   Glyph{Chars: "#"},
+		Head:   Glyph{Chars: "@"},
+		Body:   Glyph{Chars: "O"},
+		Tail:   Glyph{Chars: "O"},
+		Fruit:  Glyph{Chars: "F"},
+		Golden: Glyph{Chars: "G"},
+		Timed:  Glyph{Chars: "T"},
+		Poison: Glyph{Chars: "P"},
+	},
+	{
+		Name:   "unicode",
+		Empty:  Glyph{Chars: "·"},
+		Wall:   Glyph{Chars: "█", Fg: palette(4)},
+		Head:   Glyph{Chars: "▶◀▲▼", Fg: palette(2)},
+		Body:   Glyph{Chars: "━┃┗┛┏┓", Fg: palette(2)},
+		Tail:   Glyph{Chars: "╺╸╹╻", Fg: palette(2)},
+		Fruit:  Glyph{Chars: "●", Fg: palette(1)},
+		Golden: Glyph{Chars: "★", Fg: palette(3)},
+		Timed:  Glyph{Chars: "◆", Fg: palette(6)},
+		Poison: Glyph{Chars: "✖", Fg: palette(5)},
+	},
+	{
+		Name:   "colorblind",
+		Empty:  Glyph{Chars: "·", Fg: palette(244)},
+		Wall:   Glyph{Chars: "▓", Fg: palette(250)},
+		Head:   Glyph{Chars: "▶◀▲▼", Fg: palette(39)},
+		Body:   Glyph{Chars: "━┃┗┛┏┓", Fg: palette(32)},
+		Tail:   Glyph{Chars: "╺╸╹╻", Fg: palette(32)},
+		Fruit:  Glyph{Chars: "●", Fg: palette(214)},
+		Golden: Glyph{Chars: "★", Fg: palette(226)},
+		Timed:  Glyph{Chars: "◆", Fg: palette(117)},
+		Poison: Glyph{Chars: "✖", Fg: palette(166)},
+	},
+}
+
+/*ThemeNames returns the names of the themes that come with the game*/
+func ThemeNames() []string {
+	names := make([]string, len(themes))
+	for i, t := range themes {
+		names[i] = t.Name
+	}
+	return names
+}
+
+/*ThemeByName returns one of the themes that come with the game*/
+func ThemeByName(name string) (*Theme, error) {
+	for _, t := range themes {
+		if t.Name == strings.ToLower(name) {
+			theme := t
+			return &theme, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown theme %q, choose one of %s", name, strings.Join(ThemeNames(), ", "))
+}
+
+/*LoadTheme reads a theme file, the parts of the board it leaves out look like the classic theme*/
+func LoadTheme(r io.Reader) (*Theme, error) {
+	theme := themes[0]
+	theme.Name = ""
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if err := theme.parseLine(line); err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNumber, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return &theme, nil
+}
+
+func (t *Theme) parseLine(line string) error {
+	colon := strings.Index(line, ":")
+	if colon < 0 {
+		return fmt.Errorf("expected \"key: value\"")
+	}
+	key := strings.ToLower(strings.TrimSpace(line[:colon]))
+	value := strings.TrimSpace(line[colon+1:])
+	if key == "name" {
+		t.Name = value
+		return nil
+	}
+	glyph := t.glyph(key)
+	if glyph == nil {
+		return fmt.Errorf("unknown key %q", key)
+	}
+	fields := strings.Fields(value)
+	if len(fields) == 0 || len(fields) > 3 {
+		return fmt.Errorf("%s needs the glyphs and up to two colors", key)
+	}
+	chars := fields[0]
+	if chars == "space" {
+		chars = " "
+	}
+	if count := utf8.RuneCountInString(chars); count != 1 && count != glyphCount(key) {
+		return fmt.Errorf("%s takes 1 or %d glyphs, not %d", key, glyphCount(key), count)
+	}
+	parsed := Glyph{Chars: chars}
+	var err error
+	if len(fields) > 1 {
+		if parsed.Fg, err = ParseColor(fields[1]); err != nil {
+			return err
+		}
+	}
+	if len(fields) > 2 {
+		if parsed.Bg, err = ParseColor(fields[2]); err != nil {
+			return err
+		}
+	}
+	*glyph = parsed
+	return nil
+}
+
+/*glyph returns the glyph of the part of the board with the given key, nil if there is none*/
+func (t *Theme) glyph(key string) *Glyph {
+	switch key {
+	case "empty":
+		return &t.Empty
+	case "wall":
+		return &t.Wall
+	case "head":
+		return &t.Head
+	case "body":
+		return &t.Body
+	case "tail":
+		return &t.Tail
+	case "fruit":
+		return &t.Fruit
+	case "golden":
+		return &t.Golden
+	case "timed":
+		return &t.Timed
+	case "poison":
+		return &t.Poison
+	}
+	return nil
+}
+
+/*glyphCount returns how many glyphs a part of the board may have besides one*/
+func glyphCount(key string) int {
+	switch key {
+	case "head", "tail":
+		return 4
+	case "body":
+		return 6
+	}
+	return 1
+}
+
+/*ParseColor reads a color name or a number of the 256 colors palette*/
+func ParseColor(name string) (Color, error) {
+	name = strings.ToLower(name)
+	if name == "default" {
+		return ColorDefault, nil
+	}
+	for i, colorName := range colorNames {
+		if name == colorName {
+			return palette(i), nil
+		}
+	}
+	n, err := strconv.Atoi(name)
+	if err != nil || n < 0 || n > 255 {
+		return ColorDefault, fmt.Errorf("unknown color %q", name)
+	}
+	return palette(n), nil
+}
+
+/*
+DetectTerminal guesses what the terminal can show from the environment:
+Unicode when the locale is UTF-8, 256 colors when TERM or COLORTERM say so
+*/
+func DetectTerminal() TerminalSupport {
+	support := TerminalSupport{Colors: 8}
+	termName := os.Getenv("TERM")
+	if strings.Contains(termName, "256color") || os.Getenv("COLORTERM") != "" {
+		support.Colors = 256
+	}
+	if termName == "dumb" {
+		support.Colors = 0
+	}
+	locale := os.Getenv("LC_ALL")
+	if locale == "" {
+		locale = os.Getenv("LC_CTYPE")
+	}
+	if locale == "" {
+		locale = os.Getenv("LANG")
+	}
+	locale = strings.ToLower(locale)
+	support.Unicode = strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+	if runtime.GOOS == "windows" && os.Getenv("WT_SESSION") != "" {
+		support.Unicode, support.Colors = true, 256
+	}
+	return support
+}
+
+/*
+Degrade returns a copy of the theme the terminal can show: the glyphs of the classic theme
+when it has no Unicode, the closest of the 8 basic colors when it has no 256 colors
+and the default colors when it has none
+*/
+func (t *Theme) Degrade(support TerminalSupport) *Theme {
+	degraded := *t
+	classic := themes[0]
+	for _, key := range []string{"empty", "wall", "head", "body", "tail", "fruit", "golden", "timed", "poison"} {
+		glyph := degraded.glyph(key)
+		if !support.Unicode && !isASCII(glyph.Chars) {
+			glyph.Chars = classic.glyph(key).Chars
+		}
+		glyph.Fg = degradeColor(glyph.Fg, support.Colors)
+		glyph.Bg = degradeColor(glyph.Bg, support.Colors)
+	}
+	return &degraded
+}
+
+func isASCII(s string) bool {
+	for _, r := range s {
+		if r >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+/*degradeColor returns the color the terminal shows closest to c*/
+func degradeColor(c Color, colors int) Color {
+	n := int(c) - 1
+	switch {
+	case c == ColorDefault || colors >= 256:
+		return c
+	case colors < 8:
+		return ColorDefault
+	case n < 8:
+		return c
+	case n < 16:
+		return palette(n - 8)
+	case n < 232:
+		cube := n - 16
+		basic := 0
+		if cube/36 >= 2 {
+			basic |= 1
+		}
+		if cube/6%6 >= 2 {
+			basic |= 2
+		}
+		if cube%6 >= 2 {
+			basic |= 4
+		}
+		return palette(basic)
+	case n >= 244:
+		return palette(7)
+	}
+	return ColorDefault
+}
+
+/*char returns the i-th glyph, or the only one when there is just one*/
+func (g Glyph) char(i int) rune {
+	chars := []rune(g.Chars)
+	if len(chars) == 0 {
+		return ' '
+	}
+	if i < 0 || i >= len(chars) {
+		return chars[0]
+	}
+	return chars[i]
+}
+
+/*SetTheme sets the look of the default game*/
+func SetTheme(t *Theme) {
+	defaultGame.SetTheme(t)
+}
+
+/*SetTheme sets how HumanPlay draws the board, what the terminal can't show is replaced by what it can*/
+func (g *Game) SetTheme(t *Theme) {
+	g.theme = t.Degrade(DetectTerminal())
+}
@@ -0,0 +1,72 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package piton
+
+/*maxQueuedTurns is how many turns the player can press ahead of the snake*/
+const maxQueuedTurns = 3
+
+/*
+QueueTurn adds a turn to be applied on one of the next moves, one turn per move.
+The turn is dropped and false returned when the queue is full, when it doesn't change the direction
+or when it would make the snake go back on its neck
+*/
+func (g *Game) QueueTurn(direction int) bool {
+	if direction < Right || direction > Down || len(g.turns) == maxQueuedTurns {
+		return false
+	}
+	last, facing := g.direction, g.heading()
+	if len(g.turns) > 0 {
+		last = g.turns[len(g.turns)-1]
+		facing = last
+	}
+	if direction == last || direction == opposite(facing) {
+		return false
+	}
+	g.turns = append(g.turns, direction)
+	return true
+}
+
+/*nextTurn applies the oldest queued turn*/
+func (g *Game) nextTurn() {
+	if len(g.turns) > 0 {
+		g.direction = g.turns[0]
+		g.turns = append(g.turns[:0], g.turns[1:]...)
+	}
+}
+
+/*heading returns where the head of the snake points, even when it is standing still*/
+func (g *Game) heading() int {
+	if g.direction >= Right && g.direction <= Down {
+		return g.direction
+	}
+	if g.body.len() < 2 {
+		return none
+	}
+	head, neck := g.body.headCoord(), g.body.at(1)
+	for direction := Right; direction <= Down; direction++ {
+		if g.neighbor(neck, direction) == head {
+			return direction
+		}
+	}
+	return none
+}
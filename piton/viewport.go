@@ -0,0 +1,214 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package piton
+
+import (
+	term "github.com/nsf/termbox-go"
+)
+
+/*minViewWidth and minViewHeight are the smallest part of the board worth playing on*/
+const minViewWidth = 12
+const minViewHeight = 6
+
+/*
+screenLayout tells where things go on the terminal: the part of the board in view,
+where it is drawn and where the status panel goes
+*/
+type screenLayout struct {
+	width, height int
+	tooSmall      bool
+	left, top     int
+	viewW, viewH  int
+	hudX, hudY    int
+}
+
+/*camera is the top left cell of the board in view when the board doesn't fit in the terminal*/
+type camera struct {
+	x, y int
+}
+
+/*layout places the board and the status panel in a terminal of the given size*/
+func (g *Game) layout(width int, height int, hudRows int) screenLayout {
+	l := g.layoutWithHUD(width, height, hudRows)
+	if l.tooSmall && hudRows > 0 {
+		l = g.layoutWithHUD(width, height, 0)
+		l.hudX, l.hudY = width, height
+	}
+	return l
+}
+
+/*layoutWithHUD places the board and the status panel, or the board alone when hudRows is 0*/
+func (g *Game) layoutWithHUD(width int, height int, hudRows int) screenLayout {
+	boardW, boardH := len(g.board[0]), len(g.board)
+	l := screenLayout{width: width, height: height}
+	areaW, areaH := width, height
+	hudRight := width-hudWidth-1 >= boardW || (height-hudRows-1 < boardH && width-hudWidth-1 >= minViewWidth)
+	if hudRows == 0 {
+		hudRight = false
+	} else if hudRight {
+		areaW -= hudWidth + 1
+	} else {
+		areaH -= hudRows + 1
+	}
+	if areaW < minViewWidth || areaH < minViewHeight {
+		l.tooSmall = true
+		return l
+	}
+	l.viewW, l.viewH = min(boardW, areaW), min(boardH, areaH)
+	l.left, l.top = (areaW-l.viewW)/2, (areaH-l.viewH)/2
+	if hudRight {
+		l.hudX, l.hudY = l.left+l.viewW+1, l.top
+	} else {
+		l.hudX, l.hudY = l.left, l.top+l.viewH+1
+	}
+	return l
+}
+
+/*scrolls tells if only a part of the board is in view*/
+func (l screenLayout) scrolls(board BoardType) bool {
+	return l.viewW < len(board[0]) || l.viewH < len(board)
+}
+
+/*follow moves the camera as little as possible to keep the head away from the edges of the view*/
+func (c *camera) follow(head Coord, l screenLayout, board BoardType) {
+	c.x = followAxis(c.x, head.x, l.viewW, len(board[0]))
+	c.y = followAxis(c.y, head.y, l.viewH, len(board))
+}
+
+func followAxis(from int, head int, view int, size int) int {
+	margin := view / 4
+	if head < from+margin {
+		from = head - margin
+	}
+	if head > from+view-1-margin {
+		from = head - (view - 1 - margin)
+	}
+	if from > size-view {
+		from = size - view
+	}
+	if from < 0 {
+		from = 0
+	}
+	return from
+}
+
+/*
+drawGame draws the board, the minimap and the status panel so that they fit in the terminal,
+the board centered and scrolled to follow the head when it is too big.
+It draws a notice instead and returns false when the terminal is too small
+*/
+func (r *TermRenderer) drawGame(g *Game, hud []string) bool {
+	width, height := term.Size()
+	l := g.layout(width, height, len(hud))
+	if l.tooSmall {
+		r.shown = nil
+		term.Clear(term.ColorDefault, term.ColorDefault)
+		drawText(0, 0, "Terminal too small", term.ColorDefault, term.ColorDefault)
+		return false
+	}
+	r.view.follow(g.body.headCoord(), l, g.board)
+	cells := g.cells(r.theme)
+	frame := make([][]cell, height)
+	for y := range frame {
+		frame[y] = make([]cell, width)
+		for x := range frame[y] {
+			frame[y][x] = cell{ch: ' '}
+		}
+	}
+	for y := 0; y < l.viewH; y++ {
+		copy(frame[l.top+y][l.left:l.left+l.viewW], cells[r.view.y+y][r.view.x:r.view.x+l.viewW])
+	}
+	if g.minimap && l.scrolls(g.board) {
+		g.drawMinimap(frame, l, r.view)
+	}
+	r.drawCells(frame)
+	drawHUD(l.hudX, l.hudY, hud)
+	return true
+}
+
+/*
+drawMinimap draws the whole board shrunk in the top right corner of the view, or the bottom right one
+when the head is under the top one: the head, the body, the fruits, the poisons and the walls, with the part in view dotted
+*/
+func (g *Game) drawMinimap(frame [][]cell, l screenLayout, view camera) {
+	boardW, boardH := len(g.board[0]), len(g.board)
+	scaleX := (boardW + l.viewW/3 - 1) / (l.viewW / 3)
+	scaleY := (boardH + l.viewH/3 - 1) / (l.viewH / 3)
+	mapW, mapH := (boardW+scaleX-1)/scaleX, (boardH+scaleY-1)/scaleY
+	left, top := l.left+l.viewW-mapW, l.top
+	head := g.body.headCoord()
+	if head.x-view.x >= l.viewW-mapW && head.y-view.y < mapH {
+		top = l.top + l.viewH - mapH
+	}
+	for my := 0; my < mapH; my++ {
+		for mx := 0; mx < mapW; mx++ {
+			ch := ' '
+			rank := 0
+			for y := my * scaleY; y < (my+1)*scaleY && y < boardH; y++ {
+				for x := mx * scaleX; x < (mx+1)*scaleX && x < boardW; x++ {
+					if c, r := minimapGlyph(g.board[y][x], x == head.x && y == head.y); r > rank {
+						ch, rank = c, r
+					}
+					if rank == 0 && x >= view.x && x < view.x+l.viewW && y >= view.y && y < view.y+l.viewH {
+						ch = '.'
+					}
+				}
+			}
+			frame[top+my][left+mx] = cell{ch: ch}
+		}
+	}
+}
+
+/*minimapGlyph returns how a cell shows on the minimap and how much it matters, 0 being not at all*/
+func minimapGlyph(value int, head bool) (rune, int) {
+	switch {
+	case head:
+		return '@', 5
+	case value >= Snake:
+		return 'o', 4
+	case IsFruit(value):
+		return '*', 3
+	case value == Poison:
+		return 'x', 2
+	case value == Wall:
+		return '#', 1
+	}
+	return ' ', 0
+}
+
+/*SetMinimap shows or hides the minimap of the default game*/
+func SetMinimap(show bool) {
+	defaultGame.SetMinimap(show)
+}
+
+/*SetMinimap shows or hides a small map of the whole board when the board doesn't fit in the terminal*/
+func (g *Game) SetMinimap(show bool) {
+	g.minimap = show
+}
+
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
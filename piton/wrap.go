@@ -0,0 +1,115 @@
+/*
+SERPENT - a simple program to play a famous game in text mode
+Copyright 2019 Eugenio Menegatti
+myindievg@gmail.com
+
+	 This file is part of SERPENT.
+	 The file COPYING describes the terms under which SERPENT is distributed.
+
+   SERPENT is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   SERPENT is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with SERPENT.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package piton
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*WrapLeft makes the snake leaving from the left edge come back from the right edge*/
+const WrapLeft = 1
+
+/*WrapRight makes the snake leaving from the right edge come back from the left edge*/
+const WrapRight = 2
+
+/*WrapTop makes the snake leaving from the top edge come back from the bottom edge*/
+const WrapTop = 4
+
+/*WrapBottom makes the snake leaving from the bottom edge come back from the top edge*/
+const WrapBottom = 8
+
+/*WrapHorizontal wraps the left and right edges*/
+const WrapHorizontal = WrapLeft | WrapRight
+
+/*WrapVertical wraps the top and bottom edges*/
+const WrapVertical = WrapTop | WrapBottom
+
+/*WrapAll makes the board a torus*/
+const WrapAll = WrapHorizontal | WrapVertical
+
+/*ParseWrap reads a list of edges like "left,right", "horizontal", "vertical", "all" or "none"*/
+func ParseWrap(edges string) (int, error) {
+	wrap := 0
+	for _, edge := range strings.FieldsFunc(strings.ToLower(edges), func(r rune) bool {
+		return r == ',' || r == ' '
+	}) {
+		switch edge {
+		case "left":
+			wrap |= WrapLeft
+		case "right":
+			wrap |= WrapRight
+		case "top":
+			wrap |= WrapTop
+		case "bottom":
+			wrap |= WrapBottom
+		case "horizontal":
+			wrap |= WrapHorizontal
+		case "vertical":
+			wrap |= WrapVertical
+		case "all":
+			wrap |= WrapAll
+		case "none":
+		default:
+			return 0, fmt.Errorf("unknown edge %q", edge)
+		}
+	}
+	return wrap, nil
+}
+
+/*neighbor returns the cell next to c in the given direction, coming back from the opposite edge when it wraps*/
+func (g *Game) neighbor(c Coord, direction int) Coord {
+	c = nextCoord(c, direction)
+	wrap := g.config.Wrap
+	switch {
+	case c.x == 0 && wrap&WrapLeft != 0:
+		c.x = g.config.Width
+	case c.x == g.config.Width+1 && wrap&WrapRight != 0:
+		c.x = 1
+	case c.y == 0 && wrap&WrapTop != 0:
+		c.y = g.config.Height
+	case c.y == g.config.Height+1 && wrap&WrapBottom != 0:
+		c.y = 1
+	}
+	return c
+}
+
+/*
+nearestImage returns where the cell at x, y seems to be when looked at from the pivot,
+going through a wrapping edge when that is shorter
+*/
+func (g *Game) nearestImage(x int, y int, pivotX int, pivotY int) (int, int) {
+	w, h := g.config.Width, g.config.Height
+	wrap := g.config.Wrap
+	if dx := x - pivotX; dx > 0 && wrap&WrapLeft != 0 && w-dx < dx {
+		x -= w
+	} else if dx < 0 && wrap&WrapRight != 0 && w+dx < -dx {
+		x += w
+	}
+	if dy := y - pivotY; dy > 0 && wrap&WrapTop != 0 && h-dy < dy {
+		y -= h
+	} else if dy < 0 && wrap&WrapBottom != 0 && h+dy < -dy {
+		y += h
+	}
+	return x, y
+}